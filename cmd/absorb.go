@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewAbsorbCommand creates the absorb command
+func NewAbsorbCommand() *cobra.Command {
+	var base string
+	var yes bool
+	var rebase bool
+
+	cmd := &cobra.Command{
+		Use:   "absorb",
+		Short: "Turn staged corrections into fixup! commits for earlier branch commits",
+		Long: "Find the commit on the current branch that last touched the lines each staged hunk changes and commit the hunks " +
+			"as fixup! commits for those commits. The AI is only asked when blame points at several branch commits. Hunks " +
+			"that belong to no branch commit stay staged.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			patch, err := gitClient.GetStagedPatch()
+			if err != nil {
+				return err
+			}
+			files := git.SplitDiff(patch)
+			if len(files) == 0 {
+				return fmt.Errorf("no staged changes found")
+			}
+
+			mergeBase, err := branchBase(gitClient, base)
+			if err != nil {
+				return err
+			}
+			commits, err := gitClient.GetCommits(mergeBase + "..HEAD")
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 {
+				return fmt.Errorf("no commits on the current branch since %s", mergeBase)
+			}
+
+			units := splitUnits(files)
+			candidates, err := fixupCandidates(gitClient, files, units, commits)
+			if err != nil {
+				return err
+			}
+
+			targets := make([]string, len(units))
+			chosenByAI := make([]bool, len(units))
+			var ambiguous []int
+			for i, found := range candidates {
+				switch len(found) {
+				case 0:
+				case 1:
+					targets[i] = found[0].Hash
+				default:
+					ambiguous = append(ambiguous, i)
+				}
+			}
+
+			if len(ambiguous) > 0 {
+				aiClient, err := newAIClient(cmd)
+				if err != nil {
+					return err
+				}
+				choices, err := aiClient.ChooseFixupTargets(describeAmbiguous(files, units, candidates, ambiguous))
+				if errors.Is(err, ai.ErrDryRun) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				for _, choice := range choices.Hunks {
+					i := choice.ID - 1
+					if i < 0 || i >= len(units) || choice.Commit == "" {
+						continue
+					}
+					if hash := matchCandidate(candidates[i], choice.Commit); hash != "" {
+						targets[i], chosenByAI[i] = hash, true
+					}
+				}
+			}
+
+			view := &absorbView{}
+			var groups []splitGroup
+			var keep []int
+			// Oldest commit first, so the fixups are created in branch order
+			for j := len(commits) - 1; j >= 0; j-- {
+				commit := commits[j]
+				// The hash, unlike the subject, names exactly one commit for --autosquash
+				group := splitGroup{Message: "fixup! " + commit.Hash}
+				fixup := absorbFixup{Commit: commit.ShortHash(), Subject: commit.Subject}
+				for i, target := range targets {
+					if target != commit.Hash {
+						continue
+					}
+					group.units = append(group.units, i)
+					label := unitLabel(files, units[i])
+					if chosenByAI[i] {
+						label += " (chosen by AI)"
+					}
+					fixup.Hunks = append(fixup.Hunks, label)
+				}
+				if len(group.units) > 0 {
+					groups = append(groups, group)
+					view.Fixups = append(view.Fixups, fixup)
+				}
+			}
+			for i, target := range targets {
+				if target == "" {
+					keep = append(keep, i)
+					view.Unmatched = append(view.Unmatched, unitLabel(files, units[i]))
+				}
+			}
+
+			if err := printer.Print(view); err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				printer.Notice("None of the staged hunks belong to a commit on the branch.")
+				return nil
+			}
+
+			if !yes && !confirm(fmt.Sprintf("Create %d fixup! commit(s)?", len(groups))) {
+				printer.Notice("Aborted, nothing was committed.")
+				return nil
+			}
+			if err := commitGroups(printer.Notice, gitClient, files, units, groups, keep); err != nil {
+				return err
+			}
+
+			if rebase {
+				if err := gitClient.RebaseAutosquash(mergeBase); err != nil {
+					return err
+				}
+				printer.Notice("✅ Squashed the fixups into their commits")
+			}
+			return nil
+		},
+	}
+
+	addBaseFlag(cmd, &base)
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create the commits without asking for confirmation")
+	cmd.Flags().BoolVar(&rebase, "rebase", false, "Run an autosquash rebase after creating the fixup! commits")
+
+	return cmd
+}
+
+// branchBase returns the commit the current branch forked from base, which
+// defaults to the default branch of the configured remote
+func branchBase(gitClient *git.Client, base string) (string, error) {
+	if base == "" {
+		var err error
+		if base, err = gitClient.GetRemoteHead(viper.GetString("remote")); err != nil {
+			return "", fmt.Errorf("%w. Pass --base to name the branch's base", err)
+		}
+	}
+	return gitClient.GetMergeBase(base, "HEAD")
+}
+
+// matchCandidate returns the hash of the only candidate that starts with
+// prefix, or "" when none or several do
+func matchCandidate(candidates []git.Commit, prefix string) string {
+	var match string
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(candidate.Hash, prefix) {
+			continue
+		}
+		if match != "" {
+			return ""
+		}
+		match = candidate.Hash
+	}
+	return match
+}
+
+// fixupCandidates returns, for each unit, the branch commits that last
+// touched the lines it changes
+func fixupCandidates(gitClient *git.Client, files []git.FileDiff, units []splitUnit, commits []git.Commit) ([][]git.Commit, error) {
+	branch := map[string]git.Commit{}
+	for _, commit := range commits {
+		branch[commit.Hash] = commit
+	}
+
+	blames := map[string][]string{}
+	candidates := make([][]git.Commit, len(units))
+	for i, unit := range units {
+		file := files[unit.file]
+		var hunks []git.Hunk
+		switch {
+		case unit.hunk >= 0:
+			hunks = []git.Hunk{file.Hunks[unit.hunk]}
+		case file.NewPath == "/dev/null":
+			// A deleted file belongs to the commits that wrote its lines
+			hunks = file.Hunks
+		default:
+			// New, renamed and binary files have no lines to blame
+			continue
+		}
+
+		blame, ok := blames[file.OldPath]
+		if !ok {
+			var err error
+			if blame, err = gitClient.Blame("HEAD", file.OldPath); err != nil {
+				return nil, err
+			}
+			blames[file.OldPath] = blame
+		}
+
+		seen := map[string]bool{}
+		for _, hunk := range hunks {
+			for _, line := range blameLines(hunk) {
+				if line < 1 || line > len(blame) || seen[blame[line-1]] {
+					continue
+				}
+				if commit, ok := branch[blame[line-1]]; ok {
+					seen[commit.Hash] = true
+					candidates[i] = append(candidates[i], commit)
+				}
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// blameLines returns the lines of the old file a hunk changes. Pure
+// additions are attributed to the lines around the insertion point.
+func blameLines(hunk git.Hunk) []int {
+	var removed, around []int
+	line, previous, added := hunk.OldStart, 0, false
+	for _, l := range hunk.Lines {
+		switch {
+		case strings.HasPrefix(l, "-"):
+			removed = append(removed, line)
+			line++
+		case strings.HasPrefix(l, "+"):
+			if !added && previous > 0 {
+				around = append(around, previous)
+			}
+			added = true
+		case strings.HasPrefix(l, "\\"):
+		default:
+			if added {
+				around = append(around, line)
+				added = false
+			}
+			previous = line
+			line++
+		}
+	}
+	if len(removed) > 0 {
+		return removed
+	}
+	return around
+}
+
+// describeAmbiguous lists the ambiguous units with their candidate commits
+// as the input for choosing fixup targets
+func describeAmbiguous(files []git.FileDiff, units []splitUnit, candidates [][]git.Commit, ambiguous []int) string {
+	var b strings.Builder
+	for _, i := range ambiguous {
+		unit := units[i]
+		text := files[unit.file].Text
+		if unit.hunk >= 0 {
+			text = files[unit.file].Hunks[unit.hunk].Text()
+		}
+		fmt.Fprintf(&b, "### Hunk %d: %s\n%s\nCandidate commits:\n", i+1, files[unit.file].Path(), strings.TrimRight(text, "\n"))
+		for _, commit := range candidates[i] {
+			fmt.Fprintf(&b, "- %s %s\n", commit.ShortHash(), commit.Subject)
+		}
+		fmt.Fprintln(&b)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// absorbView is the plan shown before committing
+type absorbView struct {
+	Fixups    []absorbFixup `json:"fixups" yaml:"fixups"`
+	Unmatched []string      `json:"unmatched,omitempty" yaml:"unmatched,omitempty"`
+}
+
+// absorbFixup is a planned fixup! commit for Commit
+type absorbFixup struct {
+	Commit  string   `json:"commit" yaml:"commit"`
+	Subject string   `json:"subject" yaml:"subject"`
+	Hunks   []string `json:"hunks" yaml:"hunks"`
+}
+
+func (v *absorbView) RenderText(w io.Writer) error {
+	for _, fixup := range v.Fixups {
+		fmt.Fprintf(w, "fixup! %s %s\n", fixup.Commit, fixup.Subject)
+		for _, hunk := range fixup.Hunks {
+			fmt.Fprintf(w, "  %s\n", hunk)
+		}
+	}
+	if len(v.Unmatched) > 0 {
+		fmt.Fprintln(w, "Left staged (no matching branch commit):")
+		for _, hunk := range v.Unmatched {
+			fmt.Fprintf(w, "  %s\n", hunk)
+		}
+	}
+	return nil
+}
+
+func (v *absorbView) RenderMarkdown(w io.Writer) error {
+	for _, fixup := range v.Fixups {
+		fmt.Fprintf(w, "- `fixup!` `%s` %s\n", fixup.Commit, fixup.Subject)
+		for _, hunk := range fixup.Hunks {
+			fmt.Fprintf(w, "  - `%s`\n", hunk)
+		}
+	}
+	if len(v.Unmatched) > 0 {
+		fmt.Fprint(w, "\n**Left staged:**\n\n")
+		for _, hunk := range v.Unmatched {
+			fmt.Fprintf(w, "- `%s`\n", hunk)
+		}
+	}
+	return nil
+}
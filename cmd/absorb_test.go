@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+)
+
+func TestMatchCandidate(t *testing.T) {
+	candidates := []git.Commit{{Hash: "abc123"}, {Hash: "abd456"}}
+	tests := map[string]string{
+		"abc":    "abc123",
+		"abd456": "abd456",
+		"ab":     "",
+		"fff":    "",
+	}
+	for prefix, want := range tests {
+		if got := matchCandidate(candidates, prefix); got != want {
+			t.Errorf("matchCandidate(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+}
+
+func TestFixupSquashesIntoCommitWithSharedSubject(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 40, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	base := runGit(t, "rev-parse", "HEAD")
+
+	// Two branch commits with the same subject
+	writeLines(t, "a.txt", 40, map[int]string{2: "first"})
+	runGit(t, "commit", "--quiet", "-am", "update")
+	writeLines(t, "a.txt", 40, map[int]string{2: "first", 35: "second"})
+	runGit(t, "commit", "--quiet", "-am", "update")
+	first := runGit(t, "rev-parse", "HEAD~1")
+
+	writeLines(t, "a.txt", 40, map[int]string{2: "first fixed", 35: "second"})
+	runGit(t, "add", ".")
+	gitClient := git.NewClient()
+	patch, err := gitClient.GetStagedPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := git.SplitDiff(patch)
+	units := splitUnits(files)
+
+	groups := []splitGroup{{Message: "fixup! " + first, units: []int{0}}}
+	if err := commitGroups(func(string, ...any) {}, gitClient, files, units, groups, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := gitClient.RebaseAutosquash(base); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fix lands in the older commit, not in the newer one that shares its subject
+	if subjects := runGit(t, "log", "--format=%s"); subjects != "update\nupdate\ninitial" {
+		t.Errorf("commits =\n%s", subjects)
+	}
+	if older := runGit(t, "show", "--format=", "-U0", "HEAD~1"); !strings.Contains(older, "+first fixed") {
+		t.Errorf("older commit lacks the fix:\n%s", older)
+	}
+}
+
+func TestBlameIgnoresContentThatLooksLikeHeaders(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 3, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	initial := runGit(t, "rev-parse", "HEAD")
+
+	// A later line claims to be the porcelain header of line 1
+	writeLines(t, "a.txt", 3, map[int]string{3: initial + " 1 1 1"})
+	runGit(t, "commit", "--quiet", "-am", "tricky")
+	writeLines(t, "a.txt", 3, map[int]string{1: "changed", 3: initial + " 1 1 1"})
+	runGit(t, "commit", "--quiet", "-am", "change first line")
+	last := runGit(t, "rev-parse", "HEAD")
+
+	commits, err := git.NewClient().Blame("HEAD", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 3 || commits[0] != last || commits[1] != initial {
+		t.Errorf("Blame() = %v, want line 1 from %s", commits, last)
+	}
+}
+
+func TestRebaseAutosquashKeepsStagedChangesStaged(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 40, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	base := runGit(t, "rev-parse", "HEAD")
+	writeLines(t, "a.txt", 40, map[int]string{2: "first"})
+	runGit(t, "commit", "--quiet", "-am", "change")
+	target := runGit(t, "rev-parse", "HEAD")
+
+	writeLines(t, "a.txt", 40, map[int]string{2: "first fixed", 35: "kept"})
+	runGit(t, "add", ".")
+	writeLines(t, "b.txt", 3, nil)
+	runGit(t, "add", "b.txt")
+	gitClient := git.NewClient()
+	patch, err := gitClient.GetStagedPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := git.SplitDiff(patch)
+	units := splitUnits(files)
+	if len(units) != 3 {
+		t.Fatalf("got %d units, want 3", len(units))
+	}
+
+	groups := []splitGroup{{Message: "fixup! " + target, units: []int{0}}}
+	if err := commitGroups(func(string, ...any) {}, gitClient, files, units, groups, []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	// An unstaged change next to the staged ones
+	writeLines(t, "b.txt", 3, map[int]string{1: "unstaged"})
+
+	if err := gitClient.RebaseAutosquash(base); err != nil {
+		t.Fatal(err)
+	}
+	if subjects := runGit(t, "log", "--format=%s"); subjects != "change\ninitial" {
+		t.Errorf("commits =\n%s", subjects)
+	}
+	if staged := runGit(t, "diff", "--cached", "--name-only"); staged != "a.txt\nb.txt" {
+		t.Errorf("staged files = %q, want the kept hunks", staged)
+	}
+	if unstaged := runGit(t, "diff", "--name-only"); unstaged != "b.txt" {
+		t.Errorf("unstaged files = %q", unstaged)
+	}
+	if stashes := runGit(t, "stash", "list"); stashes != "" {
+		t.Errorf("stash left behind: %s", stashes)
+	}
+}
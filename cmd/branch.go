@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// maxSlugLength caps the description part of generated branch names
+const maxSlugLength = 50
+
+// NewBranchCommand creates the branch command
+func NewBranchCommand() *cobra.Command {
+	var ticket string
+	var create bool
+
+	cmd := &cobra.Command{
+		Use:   "branch [description]",
+		Short: "Generate a branch name",
+		Long: "Propose a branch name from a description of the planned work or, without one, from the changes in the " +
+			"working tree. The name follows the branch.pattern setting (default {type}/{ticket}-{slug}), where " +
+			"placeholders that come out empty are dropped with their separator.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			var input string
+			if len(args) == 1 && strings.TrimSpace(args[0]) != "" {
+				input = "Description of the planned work:\n" + args[0]
+			} else {
+				diff, err := gitClient.GetUnifiedDiff()
+				if err != nil {
+					return err
+				}
+				if diff == "" {
+					return fmt.Errorf("no description given and no changes in the working tree. Untracked files are not considered")
+				}
+				input = "Diff of the changes:\n" + diff
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			parts, err := aiClient.GenerateBranchName(input)
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if ticket != "" {
+				parts.Ticket = ticket
+			}
+
+			branch := &branchName{
+				Type:   refComponent(strings.ToLower(parts.Type)),
+				Ticket: refComponent(strings.TrimPrefix(parts.Ticket, "#")),
+				Slug:   truncateSlug(refComponent(strings.ToLower(parts.Slug)), maxSlugLength),
+			}
+			if branch.Name, err = formatBranchName(viper.GetString("branch.pattern"), branch); err != nil {
+				return err
+			}
+			if err := gitClient.CheckBranchName(branch.Name); err != nil {
+				return err
+			}
+
+			if err := printer.Print(branch); err != nil {
+				return err
+			}
+
+			if create {
+				if err := gitClient.CreateBranch(branch.Name); err != nil {
+					return err
+				}
+				printer.Notice("✅ Switched to a new branch %s", branch.Name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&ticket, "ticket", "", "Ticket reference to use instead of one found in the description")
+	cmd.Flags().BoolVarP(&create, "create", "c", false, "Create the branch and switch to it")
+
+	return cmd
+}
+
+// branchName is a generated branch name and the parts it was built from
+type branchName struct {
+	Name   string `json:"name" yaml:"name"`
+	Type   string `json:"type" yaml:"type"`
+	Ticket string `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+	Slug   string `json:"slug" yaml:"slug"`
+}
+
+// RenderText writes only the name, so it can be used in scripts
+func (b *branchName) RenderText(w io.Writer) error {
+	_, err := fmt.Fprintln(w, b.Name)
+	return err
+}
+
+func (b *branchName) RenderMarkdown(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "`%s`\n", b.Name)
+	return err
+}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{(\w+)\}([-_.]?)`)
+	// Characters git does not allow in ref names, and ones best avoided
+	invalidRefChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	// Separators left dangling next to a slash or at either end
+	danglingSeparators = regexp.MustCompile(`[-_.]+(/|$)|(^|/)[-_.]+`)
+	repeatedSlashes    = regexp.MustCompile(`/{2,}`)
+	repeatedDots       = regexp.MustCompile(`\.{2,}`)
+)
+
+// formatBranchName fills the {type}, {ticket} and {slug} placeholders of
+// pattern. An empty placeholder is dropped together with the separator
+// following it.
+func formatBranchName(pattern string, branch *branchName) (string, error) {
+	values := map[string]string{"type": branch.Type, "ticket": branch.Ticket, "slug": branch.Slug}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(pattern, -1) {
+		if _, ok := values[match[1]]; !ok {
+			return "", fmt.Errorf("unknown placeholder {%s} in branch.pattern", match[1])
+		}
+	}
+
+	name := placeholderPattern.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		match := placeholderPattern.FindStringSubmatch(placeholder)
+		if values[match[1]] == "" {
+			return ""
+		}
+		return values[match[1]] + match[2]
+	})
+	name = danglingSeparators.ReplaceAllString(name, "$1$2")
+	name = strings.Trim(repeatedSlashes.ReplaceAllString(name, "/"), "/")
+	if name == "" {
+		return "", fmt.Errorf("branch.pattern %q produced an empty branch name", pattern)
+	}
+	return name, nil
+}
+
+// refComponent replaces runs of characters that do not belong in a branch
+// name with a hyphen
+func refComponent(s string) string {
+	s = invalidRefChars.ReplaceAllString(strings.TrimSpace(s), "-")
+	s = repeatedDots.ReplaceAllString(s, ".")
+	return strings.Trim(s, "-_.")
+}
+
+// truncateSlug shortens slug to at most max characters, cutting at a hyphen
+// where possible
+func truncateSlug(slug string, max int) string {
+	if len(slug) <= max {
+		return slug
+	}
+	slug = slug[:max]
+	if i := strings.LastIndex(slug, "-"); i > 0 {
+		slug = slug[:i]
+	}
+	return strings.Trim(slug, "-_.")
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/richardamare/gitai/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCommand creates the cache command
+func NewCacheCommand() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and manage the AI response cache",
+		Long:  "Inspect and manage the on-disk cache of AI responses, keyed by provider, model, prompt and diff.",
+	}
+
+	cacheCmd.AddCommand(NewCacheStatsCommand())
+	cacheCmd.AddCommand(NewCacheClearCommand())
+
+	return cacheCmd
+}
+
+func NewCacheStatsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache location, size and entry count",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			store, err := newCache()
+			if err != nil {
+				return err
+			}
+
+			stats, err := store.Stats()
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(cacheStatsReport(stats))
+		},
+	}
+}
+
+func NewCacheClearCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached responses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			store, err := newCache()
+			if err != nil {
+				return err
+			}
+
+			if err := store.Clear(); err != nil {
+				return err
+			}
+
+			printer.Notice("✅ Cache cleared")
+			return nil
+		},
+	}
+}
+
+// cacheStatsReport renders cache statistics for the stats command
+type cacheStatsReport cache.Stats
+
+func (r cacheStatsReport) RenderText(w io.Writer) error {
+	fmt.Fprintf(w, "Directory: %s\n", r.Dir)
+	fmt.Fprintf(w, "Entries:   %d (%d expired)\n", r.Entries, r.Expired)
+	fmt.Fprintf(w, "Size:      %s\n", formatBytes(r.Size))
+	if r.Entries > 0 {
+		fmt.Fprintf(w, "Oldest:    %s\n", r.Oldest.Format(time.RFC3339))
+		fmt.Fprintf(w, "Newest:    %s\n", r.Newest.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (r cacheStatsReport) RenderMarkdown(w io.Writer) error {
+	fmt.Fprintln(w, "| Directory | Entries | Expired | Size |")
+	fmt.Fprintln(w, "|---|---|---|---|")
+	fmt.Fprintf(w, "| `%s` | %d | %d | %s |\n", r.Dir, r.Entries, r.Expired, formatBytes(r.Size))
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/changelog"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// NewChangelogCommand creates the changelog command
+func NewChangelogCommand() *cobra.Command {
+	var noAI bool
+	var file string
+	var version string
+
+	cmd := &cobra.Command{
+		Use:   "changelog <from>..<to>",
+		Short: "Generate a changelog section from commit history",
+		Long: "Group the commits in a range by Conventional Commit type, rewrite them into user-facing entries with AI " +
+			"and write them as a Keep a Changelog section to CHANGELOG.md. The section is named after <to> if it is a tag, " +
+			"and Unreleased otherwise.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			from, to, err := parseRange(args[0])
+			if err != nil {
+				return err
+			}
+			commits, err := gitClient.GetCommits(from + ".." + to)
+			if err != nil {
+				return err
+			}
+
+			date := ""
+			if version == "" {
+				version = changelog.Unreleased
+				if gitClient.IsTag(to) {
+					version = versionName(to)
+				}
+			}
+			if version != changelog.Unreleased {
+				if date, err = gitClient.GetCommitDate(to); err != nil {
+					return err
+				}
+			}
+
+			release := changelog.Build(version, date, commits)
+			if len(release.Unconventional) > 0 {
+				printer.Notice("⚠️  Left out %d commit(s) that are not Conventional Commits:\n  %s",
+					len(release.Unconventional), strings.Join(release.Unconventional, "\n  "))
+			}
+			if release.Empty() {
+				printer.Notice("No user-facing changes found in %s..%s.", from, to)
+				return nil
+			}
+
+			if !noAI {
+				aiClient, err := newAIClient(cmd)
+				if err != nil {
+					return err
+				}
+				notes, err := aiClient.GenerateChangelog(release.PromptInput())
+				if errors.Is(err, ai.ErrDryRun) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				release.Apply(notes)
+			}
+
+			if file == "-" {
+				return printer.Print(release)
+			}
+
+			if !filepath.IsAbs(file) {
+				root, err := gitClient.GetRepoRoot()
+				if err != nil {
+					return err
+				}
+				file = filepath.Join(root, file)
+			}
+			if err := changelog.UpdateFile(file, release); err != nil {
+				return err
+			}
+			printer.Notice("✅ Updated %s with %s", file, release.Heading())
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noAI, "no-ai", false, "Only group the commit subjects, without rewriting them with AI")
+	cmd.Flags().StringVar(&file, "file", "CHANGELOG.md", "Changelog to update, relative to the repository root, or - to print the section")
+	cmd.Flags().StringVar(&version, "version", "", "Version to name the section after (default: <to> if it is a tag, else Unreleased)")
+
+	return cmd
+}
+
+// parseRange splits "<from>..<to>" into its revisions; a single revision
+// ranges up to HEAD
+func parseRange(arg string) (string, string, error) {
+	from, to, found := strings.Cut(arg, "..")
+	to = strings.TrimPrefix(to, ".")
+	if from == "" {
+		return "", "", fmt.Errorf("invalid range %q: the start revision is missing", arg)
+	}
+	if !found || to == "" {
+		to = "HEAD"
+	}
+	return from, to, nil
+}
+
+// versionName strips the "v" prefix of tags like v1.2.0
+func versionName(tag string) string {
+	if rest, ok := strings.CutPrefix(tag, "v"); ok && rest != "" && rest[0] >= '0' && rest[0] <= '9' {
+		return rest
+	}
+	return tag
+}
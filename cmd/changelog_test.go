@@ -0,0 +1,27 @@
+package cmd
+
+import "testing"
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		arg      string
+		from, to string
+	}{
+		{"v1.0.0", "v1.0.0", "HEAD"},
+		{"v1.0.0..", "v1.0.0", "HEAD"},
+		{"v1.0.0..v1.1.0", "v1.0.0", "v1.1.0"},
+		{"main...feature", "main", "feature"},
+	}
+	for _, tt := range tests {
+		from, to, err := parseRange(tt.arg)
+		if err != nil || from != tt.from || to != tt.to {
+			t.Errorf("parseRange(%q) = %q, %q, %v, want %q, %q", tt.arg, from, to, err, tt.from, tt.to)
+		}
+	}
+
+	for _, arg := range []string{"..HEAD", "...main", ".."} {
+		if from, to, err := parseRange(arg); err == nil {
+			t.Errorf("parseRange(%q) = %q, %q, want an error", arg, from, to)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/ci"
+	"github.com/richardamare/gitai/internal/forge"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+	"github.com/richardamare/gitai/internal/output"
+	"github.com/richardamare/gitai/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// ciOptions overrides what is detected from the CI environment
+type ciOptions struct {
+	base string
+	head string
+}
+
+// NewCICommand creates the ci command group for running in pipelines
+func NewCICommand() *cobra.Command {
+	var opts ciOptions
+
+	ciCmd := &cobra.Command{
+		Use:   "ci",
+		Short: "Review and describe merge requests from a CI pipeline",
+		Long: "Run gitai non-interactively in a merge request pipeline. The CI system, the commits to compare and the merge request " +
+			"are detected from the environment variables of GitLab CI, GitHub Actions and Gitea Actions.",
+	}
+
+	ciCmd.PersistentFlags().StringVar(&opts.base, "base", "", "Commit to compare against instead of the merge request's base from the environment")
+	ciCmd.PersistentFlags().StringVar(&opts.head, "head", "", "Commit to review instead of the one under test (default HEAD)")
+
+	ciCmd.AddCommand(NewCIReviewCommand(&opts))
+	ciCmd.AddCommand(NewCIDescribeCommand(&opts))
+
+	return ciCmd
+}
+
+func NewCIReviewCommand(opts *ciOptions) *cobra.Command {
+	var format string
+	var reportFile string
+	var failOn string
+	var post bool
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Review the merge request the pipeline runs for",
+		Long: "This command reviews the changes of the merge request the pipeline runs for, prints the findings to the job log and " +
+			"writes them as a report the CI system picks up: GitLab Code Quality on GitLab CI, SARIF elsewhere.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			if failOn != "" {
+				if failOn, err = models.ParseSeverity(failOn); err != nil {
+					return err
+				}
+			}
+
+			env, diff, err := ciDiff(opts)
+			if err != nil {
+				return err
+			}
+
+			defaultFormat, defaultFile := ciReportDefaults(env)
+			if format == "" {
+				format = defaultFormat
+			}
+			if reportFile == "" {
+				reportFile = defaultFile
+			}
+			writer, err := report.Lookup(format)
+			if err != nil {
+				return err
+			}
+
+			// An empty review still writes a report so artifact uploads succeed
+			reviewDetails := &models.MrReviewDetails{}
+			if diff == "" {
+				printer.Notice("No changes found between %s and %s.", env.Base, headName(env))
+			} else {
+				aiClient, err := newAIClient(cmd)
+				if err != nil {
+					return err
+				}
+				reviewDetails, err = aiClient.ReviewMR(diff)
+				if errors.Is(err, ai.ErrDryRun) {
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("failed to generate MR review from AI: %w", err)
+				}
+			}
+
+			if err := printer.Print(reviewDetails); err != nil {
+				return err
+			}
+			if err := writeReview(printer, writer, reportFile, reviewDetails); err != nil {
+				return err
+			}
+
+			if post {
+				f, mr, err := ciMergeRequest(env)
+				if err != nil {
+					return err
+				}
+				if err := publishReview(printer, f, mr, reviewDetails, diff); err != nil {
+					return err
+				}
+			}
+
+			return checkFailOn(cmd, reviewDetails, failOn)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", fmt.Sprintf("Report format (%s; default codequality on GitLab CI, sarif elsewhere)", strings.Join(report.Formats(), ", ")))
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "Report file (default gl-code-quality-report.json on GitLab CI, gitai.sarif elsewhere)")
+	cmd.Flags().BoolVar(&post, "post", false, "Post the findings as comments on the merge request")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Exit with status %d if any finding has at least this severity (%s)", exitFindings, strings.Join(models.Severities, ", ")))
+
+	return cmd
+}
+
+func NewCIDescribeCommand(opts *ciOptions) *cobra.Command {
+	var apply bool
+
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Generate a title and description for the merge request the pipeline runs for",
+		Long:  "This command generates a title and description for the merge request the pipeline runs for and, with --apply, updates the merge request with them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			env, diff, err := ciDiff(opts)
+			if err != nil {
+				return err
+			}
+
+			if diff == "" {
+				printer.Notice("No changes found between %s and %s.", env.Base, headName(env))
+				return nil
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+			details, err := aiClient.GenerateMRDetails(diff)
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to generate MR details from AI: %w", err)
+			}
+
+			if err := printer.Print(details); err != nil {
+				return err
+			}
+
+			if apply {
+				f, mr, err := ciMergeRequest(env)
+				if err != nil {
+					return err
+				}
+				return updateMergeRequest(printer, f, mr, details.Title, output.MRDescription(details))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&apply, "apply", false, "Update the title and description of the merge request")
+
+	return cmd
+}
+
+// ciDiff detects the CI environment and returns the diff of its merge request.
+// Outside a supported CI system --base is required.
+func ciDiff(opts *ciOptions) (*ci.Environment, string, error) {
+	env, err := ci.Detect()
+	if errors.Is(err, ci.ErrNotDetected) && opts.base != "" {
+		env, err = &ci.Environment{Name: "unknown CI"}, nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w. Run in a GitLab CI, GitHub Actions or Gitea Actions pipeline, or pass --base", err)
+	}
+
+	if opts.base != "" {
+		env.Base = opts.base
+	}
+	gitClient := git.NewClient()
+	if opts.head != "" {
+		// Comments are anchored to the reviewed commit
+		head, err := gitClient.ResolveCommit(opts.head)
+		if err != nil {
+			return nil, "", err
+		}
+		env.Head, env.HeadSHA = head, head
+	}
+	if env.Base == "" {
+		return nil, "", fmt.Errorf("cannot determine the base commit from the %s environment. Run in a merge request pipeline or pass --base", env.Name)
+	}
+
+	diff, err := gitClient.GetDiffBetween(env.Base, env.Head)
+	if err != nil {
+		return nil, "", err
+	}
+	return env, diff, nil
+}
+
+// ciMergeRequest returns the forge and merge request of a merge request pipeline
+func ciMergeRequest(env *ci.Environment) (forge.Forge, *forge.MergeRequest, error) {
+	if env.MergeRequest == 0 {
+		return nil, nil, fmt.Errorf("no merge request found in the %s environment. Run in a merge request pipeline", env.Name)
+	}
+
+	serverURL, err := url.Parse(env.ServerURL)
+	if err != nil || serverURL.Hostname() == "" || env.Project == "" {
+		return nil, nil, fmt.Errorf("cannot determine the forge and project from the %s environment", env.Name)
+	}
+
+	f, err := openForge(forge.Config{Host: serverURL.Hostname(), Type: env.Forge, URL: env.ServerURL}, env.Project)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, &forge.MergeRequest{
+		Number:       env.MergeRequest,
+		URL:          env.MergeRequestURL,
+		SourceBranch: env.SourceBranch,
+		TargetBranch: env.TargetBranch,
+		BaseSHA:      env.BaseSHA,
+		HeadSHA:      env.HeadSHA,
+	}, nil
+}
+
+// ciReportDefaults returns the report format and file the CI system picks up
+func ciReportDefaults(env *ci.Environment) (format, file string) {
+	if env.Forge == forge.TypeGitLab {
+		return "codequality", "gl-code-quality-report.json"
+	}
+	return "sarif", "gitai.sarif"
+}
+
+// headName describes the reviewed commit for messages
+func headName(env *ci.Environment) string {
+	if env.Head == "" {
+		return "HEAD"
+	}
+	return env.Head
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/cache"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/output"
+	"github.com/richardamare/gitai/internal/redact"
+	"github.com/richardamare/gitai/internal/usage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// tracker accumulates the AI usage of the running command
+var tracker *usage.Tracker
+
+// newAIClient creates an AI client configured from flags, environment and config
+func newAIClient(cmd *cobra.Command) (*ai.Client, error) {
+	var opts []ai.Option
+	if viper.GetBool("redact.enabled") {
+		redactor, err := redact.New(viper.GetStringMapString("redact.patterns"), viper.GetStringSlice("redact.allow"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, ai.WithRedactor(redactor, printRedactions))
+	}
+
+	if viper.GetBool("dry_run") {
+		handler, err := dryRunPrinter(viper.GetString("dry_run_format"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, ai.WithDryRun(handler))
+		return ai.NewClient(viper.GetString("openai_api_key"), opts...), nil
+	}
+
+	apiKey := viper.GetString("openai_api_key")
+	if apiKey == "" {
+		return nil, fmt.Errorf("OpenAI API key not found. Set OPENAI_API_KEY environment variable")
+	}
+
+	if !viper.GetBool("no_cache") {
+		store, err := newCache()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, ai.WithCache(store))
+	}
+
+	var err error
+	if tracker, err = newTracker(cmd); err != nil {
+		return nil, err
+	}
+	opts = append(opts, ai.WithUsage(tracker))
+
+	return ai.NewClient(apiKey, opts...), nil
+}
+
+// newPrinter creates a printer for the format selected with --output
+func newPrinter() (*output.Printer, error) {
+	format, err := output.ParseFormat(viper.GetString("output"))
+	if err != nil {
+		return nil, err
+	}
+	return output.NewPrinter(os.Stdout, format), nil
+}
+
+// newCache opens the response cache using the configured TTL and size limit
+func newCache() (*cache.Cache, error) {
+	dir := viper.GetString("cache.dir")
+	if dir == "" {
+		var err error
+		if dir, err = cache.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return cache.New(dir, viper.GetDuration("cache.ttl"), int64(viper.GetSizeInBytes("cache.max_size"))), nil
+}
+
+// newLedger opens the usage ledger at its configured location
+func newLedger() (*usage.Ledger, error) {
+	path := viper.GetString("usage.ledger")
+	if path == "" {
+		var err error
+		if path, err = usage.DefaultLedgerPath(); err != nil {
+			return nil, err
+		}
+	}
+	return usage.NewLedger(path), nil
+}
+
+// newPrices returns the built-in price table with configured overrides applied
+func newPrices() (usage.Prices, error) {
+	var overrides usage.Prices
+	if err := viper.UnmarshalKey("pricing", &overrides); err != nil {
+		return nil, fmt.Errorf("invalid pricing config: %w", err)
+	}
+	return usage.DefaultPrices().Merge(overrides), nil
+}
+
+// newTracker creates a usage tracker attributed to cmd and the current repository
+func newTracker(cmd *cobra.Command) (*usage.Tracker, error) {
+	ledger, err := newLedger()
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := newPrices()
+	if err != nil {
+		return nil, err
+	}
+
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	repo := ""
+	if root, err := git.NewClient().GetRepoRoot(); err == nil {
+		repo = filepath.Base(root)
+	}
+
+	return usage.NewTracker(ledger, prices, viper.GetFloat64("budget.monthly"), command, repo), nil
+}
+
+// printUsageSummary reports the usage of the finished command when --verbose is set
+func printUsageSummary() {
+	if tracker == nil || !viper.GetBool("verbose") {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "AI usage: %s\n", tracker.Summary())
+}
+
+// printRedactions warns the user about values removed before sending a request
+func printRedactions(findings []redact.Finding) {
+	fmt.Fprintf(os.Stderr, "⚠️  Redacted %d potential secret(s) before sending to the AI provider:\n", len(findings))
+	for _, finding := range findings {
+		fmt.Fprintf(os.Stderr, "  line %d: %s -> %s\n", finding.Line, finding.Detector, finding.Placeholder)
+	}
+}
+
+// dryRunPrinter returns a handler that prints requests as text or JSON
+func dryRunPrinter(format string) (func(ai.Request) error, error) {
+	switch format {
+	case "text":
+		return func(req ai.Request) error {
+			fmt.Printf("=== %s ===\n", req.Name)
+			fmt.Printf("Provider:                %s\n", req.Provider)
+			fmt.Printf("Model:                   %s\n", req.Model)
+			fmt.Printf("Temperature:             %.1f\n", req.Temperature)
+			fmt.Printf("Estimated prompt tokens: %d\n", req.EstimatedTokens)
+			fmt.Printf("\n--- Prompt ---\n%s\n", req.Prompt)
+			fmt.Printf("\n--- Schema ---\n%s\n\n", req.Schema)
+			return nil
+		}, nil
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return func(req ai.Request) error {
+			return encoder.Encode(req)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown dry-run format %q: use text or json", format)
+	}
+}
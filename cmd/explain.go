@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// Explanation depths
+const (
+	depthBrief    = "brief"
+	depthDetailed = "detailed"
+)
+
+// NewExplainCommand creates the explain command
+func NewExplainCommand() *cobra.Command {
+	var depth string
+
+	cmd := &cobra.Command{
+		Use:   "explain <rev|range>",
+		Short: "Explain a commit or range of commits in plain language",
+		Long: "Explain what a commit or range of commits (e.g. v1.2.0..HEAD) does from its messages and diff: the intent, " +
+			"the affected components, the risk and possible follow-ups. --depth brief keeps it to a paragraph, " +
+			"--depth detailed adds a step-by-step walkthrough.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if depth != depthBrief && depth != depthDetailed {
+				return fmt.Errorf("invalid depth %q, expected %s or %s", depth, depthBrief, depthDetailed)
+			}
+
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			changes, err := explainInput(gitClient, args[0])
+			if err != nil {
+				return err
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			explanation, err := aiClient.ExplainChanges(fmt.Sprintf("Depth: %s\n\n%s", depth, changes))
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(explanation)
+		},
+	}
+
+	cmd.Flags().StringVar(&depth, "depth", depthBrief, "Level of detail: brief or detailed")
+
+	return cmd
+}
+
+// explainInput describes the commit or range arg refers to with its
+// messages and diff
+func explainInput(gitClient *git.Client, arg string) (string, error) {
+	if !strings.Contains(arg, "..") {
+		commit, diff, err := gitClient.ShowCommit(arg)
+		if err != nil {
+			return "", err
+		}
+		if diff == "" {
+			return "", fmt.Errorf("commit %s has no changes", commit.ShortHash())
+		}
+		return fmt.Sprintf("## Commit %s by %s on %s\n\n%s\n\n## Diff\n\n%s",
+			commit.ShortHash(), commit.AuthorName, commit.AuthorDate, commit.Message(),
+			git.TruncateDiff(diff, squashDiffFileLines, squashDiffLines)), nil
+	}
+
+	from, to, err := parseRange(arg)
+	if err != nil {
+		return "", err
+	}
+	commits, err := gitClient.GetCommits(from + ".." + to)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("no commits in %s", arg)
+	}
+	diff, err := gitClient.GetDiffBetween(from, to)
+	if err != nil {
+		return "", err
+	}
+	return squashInput(commitMessages(commits), diff), nil
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/forge"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+	"github.com/richardamare/gitai/internal/output"
+	"github.com/spf13/viper"
+)
+
+// newForge creates a client for the forge hosting the configured remote
+func newForge(gitClient *git.Client) (forge.Forge, error) {
+	remoteURL, err := gitClient.GetRemoteURL(viper.GetString("remote"))
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := forge.ParseRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return openForge(forge.Config{Host: remote.Host}, remote.Path)
+}
+
+// openForge creates a client for the repository at path on the forge
+// resolved by forgeConfig
+func openForge(defaults forge.Config, path string) (forge.Forge, error) {
+	cfg, err := forgeConfig(defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	return forge.New(cfg, path)
+}
+
+// forgeConfig resolves the backend for defaults.Host. An entry in the forges
+// list matching the host wins, then defaults.Type, e.g. the forge detected
+// from a CI environment, then the forge setting, then detection from the
+// host. A missing URL or token falls back to the section named after the
+// backend, e.g. gitlab.token, and the URL finally to defaults.URL.
+func forgeConfig(defaults forge.Config) (forge.Config, error) {
+	host := defaults.Host
+	var mappings []forge.Config
+	if err := viper.UnmarshalKey("forges", &mappings); err != nil {
+		return forge.Config{}, fmt.Errorf("invalid forges config: %w", err)
+	}
+
+	cfg := forge.Config{Host: host}
+	for _, mapping := range mappings {
+		if strings.EqualFold(mapping.Host, host) {
+			cfg = mapping
+			break
+		}
+	}
+
+	if cfg.Type == "" {
+		cfg.Type = defaults.Type
+	}
+	if cfg.Type == "" {
+		cfg.Type = viper.GetString("forge")
+	}
+	if cfg.Type == "" {
+		cfg.Type = detectForge(host)
+	}
+	if err := forge.CheckType(cfg.Type); err != nil {
+		return cfg, err
+	}
+	if cfg.URL == "" {
+		cfg.URL = viper.GetString(cfg.Type + ".url")
+	}
+	if cfg.URL == "" {
+		cfg.URL = defaults.URL
+	}
+	if cfg.Token == "" {
+		cfg.Token = viper.GetString(cfg.Type + ".token")
+	}
+	if cfg.Token == "" {
+		return cfg, fmt.Errorf("no %s token found for %s. Set %s.token in the config file or the %s_TOKEN environment variable",
+			cfg.Type, host, cfg.Type, strings.ToUpper(cfg.Type))
+	}
+	return cfg, nil
+}
+
+// detectForge guesses the backend for a remote host from well-known hosts
+// and the configured instance URLs, defaulting to GitLab
+func detectForge(host string) string {
+	switch host {
+	case "github.com":
+		return forge.TypeGitHub
+	case "gitlab.com":
+		return forge.TypeGitLab
+	}
+	for _, kind := range forge.Types {
+		if host == configuredHost(kind+".url") {
+			return kind
+		}
+	}
+	return forge.TypeGitLab
+}
+
+// configuredHost returns the hostname of the URL configured under key
+func configuredHost(key string) string {
+	u, err := url.Parse(viper.GetString(key))
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// findMergeRequest returns the open merge request for the current branch
+func findMergeRequest(f forge.Forge, gitClient *git.Client) (*forge.MergeRequest, string, error) {
+	branch, err := gitClient.GetCurrentBranch()
+	if err != nil {
+		return nil, "", err
+	}
+	if branch == "" {
+		return nil, "", fmt.Errorf("cannot find a merge request from a detached HEAD")
+	}
+
+	mr, err := f.FindMergeRequest(branch)
+	return mr, branch, err
+}
+
+// applyMergeRequest sets the title and description of the open merge request
+// for the current branch, creating it against target if there is none. An
+// empty description leaves the existing description untouched.
+func applyMergeRequest(printer *output.Printer, gitClient *git.Client, title, description, target string) error {
+	f, err := newForge(gitClient)
+	if err != nil {
+		return err
+	}
+
+	mr, branch, err := findMergeRequest(f, gitClient)
+	if err != nil {
+		return err
+	}
+
+	if mr != nil {
+		return updateMergeRequest(printer, f, mr, title, description)
+	}
+
+	if target == "" {
+		if target, err = f.DefaultBranch(); err != nil {
+			return err
+		}
+	}
+
+	created, err := f.CreateMergeRequest(branch, target, title, description)
+	if err != nil {
+		return err
+	}
+	printer.Notice("✅ Created merge request %d: %s", created.Number, created.URL)
+	return nil
+}
+
+// updateMergeRequest sets the title and, if non-empty, the description of mr
+func updateMergeRequest(printer *output.Printer, f forge.Forge, mr *forge.MergeRequest, title, description string) error {
+	updated, err := f.UpdateMergeRequest(mr, title, description)
+	if err != nil {
+		return err
+	}
+	printer.Notice("✅ Updated merge request %d: %s", updated.Number, cmp.Or(updated.URL, mr.URL))
+	return nil
+}
+
+// reviewedMergeRequest returns the open merge request for the current branch
+// and the changes the forge shows for it, so posted findings are anchored to
+// the same diff. Local changes would not be part of that diff, so the working
+// tree must be clean.
+func reviewedMergeRequest(gitClient *git.Client) (forge.Forge, *forge.MergeRequest, git.DiffSource, error) {
+	dirty, err := gitClient.HasChanges()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dirty {
+		return nil, nil, nil, fmt.Errorf("--post reviews the pushed commits of the merge request, but the working tree has changes. Commit or stash them first")
+	}
+
+	f, err := newForge(gitClient)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	mr, branch, err := findMergeRequest(f, gitClient)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if mr == nil {
+		return nil, nil, nil, fmt.Errorf("no open merge request found for branch %s", branch)
+	}
+	if mr.BaseSHA == "" || mr.HeadSHA == "" {
+		return nil, nil, nil, fmt.Errorf("the forge did not report the base and head commits of merge request %d", mr.Number)
+	}
+
+	return f, mr, git.Range(gitClient, mr.BaseSHA, mr.HeadSHA), nil
+}
+
+// publishReview posts the findings of review on mr
+func publishReview(printer *output.Printer, f forge.Forge, mr *forge.MergeRequest, review *models.MrReviewDetails, diff string) error {
+	poster, ok := f.(forge.ReviewPoster)
+	if !ok {
+		return fmt.Errorf("posting reviews is not supported for this forge")
+	}
+
+	result, err := poster.PostReview(mr, forge.LocateFindings(review, diff))
+	if err != nil {
+		return err
+	}
+	printer.Notice("✅ Posted %d finding(s) to merge request %d (%d already posted, %d resolved): %s",
+		result.Posted, mr.Number, result.Duplicates, result.Resolved, mr.URL)
+	return nil
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// NewHookCommand creates the hook command, which groups the entry points
+// for git hooks
+func NewHookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Run gitai from git hooks",
+		Long:  "Entry points meant to be called from git hooks with the arguments git passes to the hook",
+	}
+
+	cmd.AddCommand(NewPreCommitHookCommand())
+	cmd.AddCommand(NewPrepareCommitMsgHookCommand())
+
+	return cmd
+}
+
+// NewPrepareCommitMsgHookCommand creates the hook prepare-commit-msg command
+func NewPrepareCommitMsgHookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prepare-commit-msg <file> [<source> [<commit>]]",
+		Short: "Write merge and squash commit messages",
+		Long: "Replace the default message of merge commits and of commits after git merge --squash with a generated " +
+			"one. Merge messages note how conflicts were resolved. Other commits are left alone. Failures are reported " +
+			"as warnings and keep git's default message, so the hook never blocks a commit.\n\n" +
+			"Install it with:\n\n" +
+			"  echo 'exec gitai hook prepare-commit-msg \"$@\"' > .git/hooks/prepare-commit-msg\n" +
+			"  chmod +x .git/hooks/prepare-commit-msg",
+		Args: cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := ""
+			if len(args) > 1 {
+				source = args[1]
+			}
+			if source != "merge" && source != "squash" {
+				return nil
+			}
+
+			err := prepareCommitMsg(cmd, args[0], source)
+			if err != nil && !errors.Is(err, ai.ErrDryRun) {
+				fmt.Fprintf(os.Stderr, "⚠️  gitai: %v. Keeping the default message.\n", err)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// prepareCommitMsg replaces the message in file with one generated for the
+// merge or squash commit, keeping git's comment lines
+func prepareCommitMsg(cmd *cobra.Command, file, source string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	gitClient := git.NewClient()
+	comment := commentPrefix(gitClient.GetCommentChar(), string(content))
+	message, comments := splitComments(string(content), comment)
+
+	aiClient, err := newAIClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	var commitMsg *models.CommitMessage
+	if source == "squash" {
+		diff, err := gitClient.GetStagedDiffAgainst("HEAD")
+		if err != nil {
+			return err
+		}
+		// git writes the log of the squashed commits as the default message
+		commitMsg, err = aiClient.GenerateSquashMessage(squashInput(message, diff))
+		if err != nil {
+			return err
+		}
+	} else {
+		input, err := mergeInput(gitClient, message, conflictedFiles(comments, comment))
+		if err != nil {
+			return err
+		}
+		if input == "" {
+			return nil
+		}
+		commitMsg, err = aiClient.GenerateMergeMessage(input)
+		if err != nil {
+			return err
+		}
+	}
+	text := strings.TrimSpace(commitMsg.Message) + "\n"
+	if comments != "" {
+		text += "\n" + comments
+	}
+	if err := os.WriteFile(file, []byte(text), 0o644); err != nil {
+		return err
+	}
+	printer.Notice("✅ Generated %s commit message", source)
+	return nil
+}
+
+// mergeInput describes the merge in progress: the default message, the
+// merged commits, their diff and how conflicted files were resolved. It
+// returns an empty string if no merge is in progress.
+func mergeInput(gitClient *git.Client, message string, conflicts []string) (string, error) {
+	heads, err := gitClient.GetMergeHeads()
+	if err != nil || len(heads) == 0 {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Default merge message\n\n%s\n", message)
+	for _, head := range heads {
+		commits, err := gitClient.GetCommits("HEAD.." + head)
+		if err != nil {
+			return "", err
+		}
+		diff, err := gitClient.GetDiffBetween("HEAD", head)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\n## Merged commits\n\n%s\n\n## Merged diff\n\n%s\n", commitMessages(commits), diff)
+
+		if len(conflicts) > 0 {
+			theirs, err := gitClient.GetStagedDiffAgainst(head, conflicts...)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "\n## Resolution compared to the merged side\n\n%s\n", theirs)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		ours, err := gitClient.GetStagedDiffAgainst("HEAD", conflicts...)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\n## Conflicted files\n\n- %s\n\n## Resolution compared to the current branch\n\n%s\n",
+			strings.Join(conflicts, "\n- "), ours)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// scissors follows the comment character on the line above the diff that
+// git commit --verbose adds; git ignores everything from it on
+const scissors = " ------------------------ >8 ------------------------"
+
+// commentPrefix returns the comment character git used in content. For
+// core.commentChar=auto git picks a character the message does not start a
+// line with, and ends the file with its own comment lines.
+func commentPrefix(commentChar, content string) string {
+	if commentChar != "auto" {
+		return commentChar
+	}
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	if last := lines[len(lines)-1]; last != "" && strings.ContainsAny(last[:1], "#;@!$%^&|:") {
+		return last[:1]
+	}
+	return "#"
+}
+
+// splitComments separates a commit message file into the message and the
+// comment lines git added to it, which start with comment
+func splitComments(content, comment string) (string, string) {
+	var message, comments []string
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if line == comment+scissors {
+			comments = append(comments, lines[i:]...)
+			break
+		}
+		if strings.HasPrefix(line, comment) {
+			comments = append(comments, line)
+		} else {
+			message = append(message, line)
+		}
+	}
+	if len(comments) == 0 {
+		return strings.TrimSpace(content), ""
+	}
+	return strings.TrimSpace(strings.Join(message, "\n")), strings.Join(comments, "\n") + "\n"
+}
+
+// conflictedFiles returns the files listed under "# Conflicts:" in the
+// comments git adds to the message of a merge with conflicts
+func conflictedFiles(comments, comment string) []string {
+	var files []string
+	inConflicts := false
+	for _, line := range strings.Split(comments, "\n") {
+		switch {
+		case strings.TrimSpace(line) == comment+" Conflicts:":
+			inConflicts = true
+		case inConflicts && strings.HasPrefix(line, comment+"\t"):
+			files = append(files, strings.TrimPrefix(line, comment+"\t"))
+		case inConflicts:
+			inConflicts = len(files) == 0 && strings.TrimSpace(line) == comment
+		}
+	}
+	return files
+}
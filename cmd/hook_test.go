@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+)
+
+func TestSplitCommentsStopsAtScissors(t *testing.T) {
+	content := "Merge branch 'feature'\n\n" +
+		"# Please enter a commit message.\n" +
+		"# ------------------------ >8 ------------------------\n" +
+		"# Do not modify or remove the line above.\n" +
+		"diff --git a/a.txt b/a.txt\n" +
+		"+added line\n"
+
+	message, comments := splitComments(content, "#")
+	if message != "Merge branch 'feature'" {
+		t.Errorf("message = %q, want only the merge message", message)
+	}
+	want := "# Please enter a commit message.\n" +
+		"# ------------------------ >8 ------------------------\n" +
+		"# Do not modify or remove the line above.\n" +
+		"diff --git a/a.txt b/a.txt\n" +
+		"+added line\n\n"
+	if comments != want {
+		t.Errorf("comments = %q, want %q", comments, want)
+	}
+}
+
+func TestSplitCommentsWithCommentChar(t *testing.T) {
+	content := "Merge branch 'feature'\n\n#123 stays in the message\n\n; Conflicts:\n;\tb.txt\n;\ta.txt\n;\n; It looks like you may be committing a merge.\n"
+
+	message, comments := splitComments(content, ";")
+	if message != "Merge branch 'feature'\n\n#123 stays in the message" {
+		t.Errorf("message = %q", message)
+	}
+	if files := conflictedFiles(comments, ";"); !reflect.DeepEqual(files, []string{"b.txt", "a.txt"}) {
+		t.Errorf("conflictedFiles() = %v", files)
+	}
+}
+
+func TestCommentPrefix(t *testing.T) {
+	tests := []struct {
+		commentChar, content, want string
+	}{
+		{"#", "Message\n\n; comment\n", "#"},
+		{";", "Message\n", ";"},
+		{"auto", "#1 Message\n\n; Conflicts:\n;\ta.txt\n", ";"},
+		{"auto", "Message\n", "#"},
+	}
+	for _, test := range tests {
+		if got := commentPrefix(test.commentChar, test.content); got != test.want {
+			t.Errorf("commentPrefix(%q, %q) = %q, want %q", test.commentChar, test.content, got, test.want)
+		}
+	}
+}
+
+func TestGetCommentChar(t *testing.T) {
+	tempRepo(t)
+	gitClient := git.NewClient()
+	if got := gitClient.GetCommentChar(); got != "#" {
+		t.Errorf("GetCommentChar() without a setting = %q, want #", got)
+	}
+	runGit(t, "config", "core.commentChar", ";")
+	if got := gitClient.GetCommentChar(); got != ";" {
+		t.Errorf("GetCommentChar() = %q, want ;", got)
+	}
+}
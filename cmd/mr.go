@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/forge"
 	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+	"github.com/richardamare/gitai/internal/output"
+	"github.com/richardamare/gitai/internal/report"
 	"github.com/spf13/cobra"
 )
 
@@ -24,103 +31,324 @@ func NewMRCommand() *cobra.Command {
 }
 
 func NewMRReviewCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "review",
+	var sourceFlags diffSourceFlags
+	var base string
+	var format string
+	var reportFile string
+	var failOn string
+	var post bool
+
+	cmd := &cobra.Command{
+		Use:   "review " + diffSourceUsage,
 		Short: "Generate a review for the current merge request",
-		Long:  "This command generates a review for the current merge request based on the git diff of the current branch.",
+		Long:  "This command generates a review for the current merge request based on the git diff of the current branch, or for the changes selected by the arguments and flags.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			if failOn != "" {
+				if failOn, err = models.ParseSeverity(failOn); err != nil {
+					return err
+				}
+			}
+
+			var writer report.Writer
+			if format != "" {
+				if writer, err = report.Lookup(format); err != nil {
+					return err
+				}
+			}
+
 			gitClient := git.NewClient()
-			diff, err := gitClient.GetDiffFromMain("master")
+			source, err := selectedSource(gitClient, &sourceFlags, args, post, "--post")
+			if err != nil {
+				return err
+			}
+
+			// With --post the forge names the commits to review, so the
+			// branch's base is not needed
+			var f forge.Forge
+			var mr *forge.MergeRequest
+			switch {
+			case post:
+				if f, mr, source, err = reviewedMergeRequest(gitClient); err != nil {
+					return err
+				}
+			case source == nil:
+				if source, err = branchSource(gitClient, base); err != nil {
+					return err
+				}
+			}
+
+			diff, err := source.Diff()
 			if err != nil {
-				return fmt.Errorf("failed to get git diff of current branch: %w", err)
+				return fmt.Errorf("failed to get git diff of %s: %w", source, err)
 			}
 
 			if diff == "" {
-				fmt.Println("No changes found on current branch.")
+				printer.Notice("No changes found in %s.", source)
 				return nil
 			}
 
-			aiClient := ai.NewClient(os.Getenv("OPENAI_API_KEY"))
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
 			reviewDetails, err := aiClient.ReviewMR(diff)
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
 			if err != nil {
 				return fmt.Errorf("failed to generate MR review from AI: %w", err)
 			}
 
-			fmt.Println("AI Review:")
-			for _, review := range reviewDetails.Review {
-				fmt.Printf("\nFile: %s:%d\n", review.File, review.Line)
-				fmt.Printf("Category: %s\n", review.Category)
-				fmt.Printf("Comment: %s\n", review.Comment)
-				if review.CodeSnippet != "" {
-					fmt.Printf("Code Snippet:\n```\n%s\n```\n", review.CodeSnippet)
+			if err := writeReview(printer, writer, reportFile, reviewDetails); err != nil {
+				return err
+			}
+
+			if post {
+				if err := publishReview(printer, f, mr, reviewDetails, diff); err != nil {
+					return err
 				}
 			}
-			return nil
+
+			return checkFailOn(cmd, reviewDetails, failOn)
 		},
 	}
+
+	sourceFlags.add(cmd)
+	addBaseFlag(cmd, &base)
+	cmd.Flags().StringVar(&format, "format", "", fmt.Sprintf("Write findings as a report (%s) instead of --output", strings.Join(report.Formats(), ", ")))
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "Write the review to this file instead of stdout")
+	cmd.Flags().BoolVar(&post, "post", false, "Review the pushed commits of the open merge request for the current branch and post the findings as comments")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Exit with status %d if any finding has at least this severity (%s)", exitFindings, strings.Join(models.Severities, ", ")))
+
+	return cmd
+}
+
+// exitFindings is the exit status when --fail-on findings are present
+const exitFindings = 2
+
+// checkFailOn returns an ExitError if the review has findings at or above
+// the failOn severity
+func checkFailOn(cmd *cobra.Command, review *models.MrReviewDetails, failOn string) error {
+	if failOn == "" {
+		return nil
+	}
+
+	findings := review.AtLeast(failOn)
+	if len(findings) == 0 {
+		return nil
+	}
+
+	cmd.SilenceUsage = true
+	return &ExitError{
+		Code:    exitFindings,
+		Message: fmt.Sprintf("%d finding(s) with severity %s or higher", len(findings), failOn),
+	}
+}
+
+// writeReview prints the review with the report writer if one was selected,
+// or in the --output format otherwise, to reportFile or stdout
+func writeReview(printer *output.Printer, writer report.Writer, reportFile string, review *models.MrReviewDetails) error {
+	w := io.Writer(os.Stdout)
+	if reportFile != "" {
+		f, err := os.Create(reportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report file: %w", err)
+		}
+		defer f.Close()
+		w = f
+		printer = output.NewPrinter(f, printer.Format())
+	}
+
+	if writer != nil {
+		tool := report.Tool{Name: "gitai", Version: AppVersion, URI: "https://github.com/richardamare/gitai"}
+		if err := writer(w, tool, review); err != nil {
+			return fmt.Errorf("failed to write review report: %w", err)
+		}
+	} else if err := printer.Print(review); err != nil {
+		return err
+	}
+
+	if reportFile != "" {
+		fmt.Fprintf(os.Stderr, "Review written to %s\n", reportFile)
+	}
+	return nil
 }
 
 func NewMRTitleCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "title",
+	var sourceFlags diffSourceFlags
+	var base string
+	var apply bool
+	var target string
+
+	cmd := &cobra.Command{
+		Use:   "title " + diffSourceUsage,
 		Short: "Generate a title for the current merge request",
-		Long:  "This command generates a title for the current merge request based on the git diff from the main branch.",
+		Long:  "This command generates a title for the current merge request based on the git diff of the current branch since it forked from its base, or for the changes selected by the arguments and flags.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
 			gitClient := git.NewClient()
-			diff, err := gitClient.GetDiffFromMain("master")
+			source, err := mrSource(gitClient, &sourceFlags, args, base, apply, "--apply")
 			if err != nil {
-				return fmt.Errorf("failed to get git diff from master branch: %w", err)
+				return err
+			}
+			diff, err := source.Diff()
+			if err != nil {
+				return fmt.Errorf("failed to get git diff of %s: %w", source, err)
 			}
 
 			if diff == "" {
-				fmt.Println("No changes found compared to main branch.")
+				printer.Notice("No changes found in %s.", source)
 				return nil
 			}
 
-			aiClient := ai.NewClient(os.Getenv("OPENAI_API_KEY"))
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
 			title, err := aiClient.GenerateMRTitle(diff)
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
 			if err != nil {
 				return fmt.Errorf("failed to generate MR title from AI: %w", err)
 			}
 
-			fmt.Printf("Generated MR Title: %s\n", title)
+			if err := printer.Print(&models.MrTitle{Title: title}); err != nil {
+				return err
+			}
+
+			if apply {
+				return applyMergeRequest(printer, gitClient, title, "", target)
+			}
 			return nil
 		},
 	}
+
+	sourceFlags.add(cmd)
+	addBaseFlag(cmd, &base)
+	addApplyFlags(cmd, &apply, &target)
+
+	return cmd
 }
 
 func NewMRDetailsCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "details",
+	var sourceFlags diffSourceFlags
+	var base string
+	var apply bool
+	var target string
+
+	cmd := &cobra.Command{
+		Use:   "details " + diffSourceUsage,
 		Short: "Generate a description for the current merge request",
-		Long:  "This command generates a description for the current merge request based on the git diff from the main branch.",
+		Long:  "This command generates a description for the current merge request based on the git diff of the current branch since it forked from its base, or for the changes selected by the arguments and flags.",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
 			gitClient := git.NewClient()
-			diff, err := gitClient.GetDiffFromMain("master")
+			source, err := mrSource(gitClient, &sourceFlags, args, base, apply, "--apply")
+			if err != nil {
+				return err
+			}
+			diff, err := source.Diff()
 			if err != nil {
-				return fmt.Errorf("failed to get git diff from master branch: %w", err)
+				return fmt.Errorf("failed to get git diff of %s: %w", source, err)
 			}
 
 			if diff == "" {
-				fmt.Println("No changes found compared to main branch.")
+				printer.Notice("No changes found in %s.", source)
 				return nil
 			}
 
-			aiClient := ai.NewClient(os.Getenv("OPENAI_API_KEY"))
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
 			details, err := aiClient.GenerateMRDetails(diff)
+			if errors.Is(err, ai.ErrDryRun) {
+				fmt.Fprintln(os.Stderr, "Note: the title and description request is built from the file summaries and is only sent after they are generated.")
+				return nil
+			}
 			if err != nil {
 				return fmt.Errorf("failed to generate MR details from AI: %w", err)
 			}
 
-			fmt.Printf("Generated MR Title: %s\n", details.Title)
-			fmt.Printf("Generated MR Description: %s\n", details.Description)
-			fmt.Println("--------------------------------")
-			for _, file := range details.FileSummaries {
-				fmt.Printf("File: %s\n", file.File)
-				fmt.Printf("Description: %s\n", file.Description)
-				fmt.Println("--------------------------------")
+			if err := printer.Print(details); err != nil {
+				return err
+			}
+
+			if apply {
+				return applyMergeRequest(printer, gitClient, details.Title, output.MRDescription(details), target)
 			}
 			return nil
 		},
 	}
-}
\ No newline at end of file
+
+	sourceFlags.add(cmd)
+	addBaseFlag(cmd, &base)
+	addApplyFlags(cmd, &apply, &target)
+
+	return cmd
+}
+
+// mrSource returns the changes selected by the source flags and args, or
+// the current branch compared to where it forked from base. Publishing the
+// result with flag (--apply or --post) only makes sense for the current
+// branch.
+func mrSource(gitClient *git.Client, sourceFlags *diffSourceFlags, args []string, base string, publish bool, flag string) (git.DiffSource, error) {
+	source, err := selectedSource(gitClient, sourceFlags, args, publish, flag)
+	if err != nil || source != nil {
+		return source, err
+	}
+	return branchSource(gitClient, base)
+}
+
+// selectedSource returns the changes selected by the source flags and args,
+// or nil if none are selected, which is the only choice when publishing
+// with flag
+func selectedSource(gitClient *git.Client, sourceFlags *diffSourceFlags, args []string, publish bool, flag string) (git.DiffSource, error) {
+	source, err := sourceFlags.source(gitClient, args)
+	if err != nil {
+		return nil, err
+	}
+	if source != nil && publish {
+		return nil, fmt.Errorf("%s only works with the changes of the current branch", flag)
+	}
+	return source, nil
+}
+
+// branchSource returns the changes of the current branch since it forked
+// from base
+func branchSource(gitClient *git.Client, base string) (git.DiffSource, error) {
+	mergeBase, err := branchBase(gitClient, base)
+	if err != nil {
+		return nil, err
+	}
+	return git.Branch(gitClient, mergeBase), nil
+}
+
+// addBaseFlag registers the flag naming the branch the current one is
+// compared to
+func addBaseFlag(cmd *cobra.Command, base *string) {
+	cmd.Flags().StringVar(base, "base", "", "Branch the current branch started from (default: the remote's default branch)")
+}
+
+// addApplyFlags registers the flags for publishing generated content to the forge
+func addApplyFlags(cmd *cobra.Command, apply *bool, target *string) {
+	cmd.Flags().BoolVar(apply, "apply", false, "Update the open merge request for the current branch, or create one")
+	cmd.Flags().StringVar(target, "target", "", "Target branch when creating a merge request (default: the project's default branch)")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+)
+
+func TestMrSourceComparesWithMergeBase(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 40, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	runGit(t, "switch", "--quiet", "-c", "feature")
+	writeLines(t, "a.txt", 40, map[int]string{2: "on feature"})
+	runGit(t, "commit", "--quiet", "-am", "feature change")
+	// main moves on after the branch forked
+	runGit(t, "switch", "--quiet", "main")
+	writeLines(t, "a.txt", 40, map[int]string{35: "on main"})
+	runGit(t, "commit", "--quiet", "-am", "main change")
+	runGit(t, "switch", "--quiet", "feature")
+
+	source, err := mrSource(git.NewClient(), &diffSourceFlags{}, nil, "main", false, "--apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := source.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+on feature") || strings.Contains(diff, "on main") {
+		t.Errorf("Diff() =\n%s", diff)
+	}
+
+	if _, err := mrSource(git.NewClient(), &diffSourceFlags{}, nil, "", false, "--apply"); err == nil || !strings.Contains(err.Error(), "--base") {
+		t.Errorf("mrSource() without a remote = %v, want a hint to pass --base", err)
+	}
+}
+
+func TestMrReviewPostSkipsBaseLookup(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 3, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	writeLines(t, "a.txt", 3, map[int]string{1: "dirty"})
+
+	// No origin/HEAD exists; the dirty tree is reported before any forge lookup
+	cmd := NewMRReviewCommand()
+	cmd.SetArgs([]string{"--post"})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "working tree has changes") {
+		t.Errorf("mr review --post = %v, want the dirty tree error", err)
+	}
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/conventional"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+// maxPatchLines limits how much of each commit's diff is sent when checking
+// for undeclared breaking changes
+const maxPatchLines = 300
+
+// NewNextVersionCommand creates the next-version command
+func NewNextVersionCommand() *cobra.Command {
+	var checkBreaking bool
+	var tag bool
+
+	cmd := &cobra.Command{
+		Use:   "next-version",
+		Short: "Recommend the next semantic version from commits since the last tag",
+		Long: "Find the latest semantic version tag, parse the commits since then as Conventional Commits and compute the " +
+			"next major, minor or patch version. Breaking changes bump the major version, or the minor version before 1.0.0.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			current, latestTag, err := latestVersion(gitClient)
+			if err != nil {
+				return err
+			}
+
+			revRange := "HEAD"
+			if latestTag != "" {
+				revRange = latestTag + "..HEAD"
+			}
+			commits, err := gitClient.GetCommits(revRange)
+			if err != nil {
+				return err
+			}
+
+			result := &nextVersion{Current: current.String(), Tag: latestTag, Commits: len(commits)}
+			parsed := make([]conventional.Commit, len(commits))
+			for i, commit := range commits {
+				parsed[i] = conventional.Parse(commit.Message())
+				if parsed[i].Breaking {
+					result.Breaking = append(result.Breaking, breakingCommit{Commit: commit.ShortHash(), Subject: commit.Subject})
+				}
+			}
+
+			var aiClient *ai.Client
+			if (checkBreaking || tag) && len(commits) > 0 {
+				if aiClient, err = newAIClient(cmd); err != nil {
+					return err
+				}
+			}
+
+			if checkBreaking && len(commits) > 0 {
+				detected, err := detectBreakingChanges(aiClient, gitClient, commits, parsed)
+				if errors.Is(err, ai.ErrDryRun) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				result.Breaking = append(result.Breaking, detected...)
+			}
+
+			bump := semver.BumpFor(current, parsed)
+			result.Bump = string(bump)
+			result.Next = current.Next(bump).String()
+
+			if err := printer.Print(result); err != nil {
+				return err
+			}
+
+			if !tag {
+				return nil
+			}
+			if bump == semver.None {
+				printer.Notice("No commits since %s, not creating a tag.", latestTag)
+				return nil
+			}
+
+			tagMsg, err := aiClient.GenerateTagMessage(releaseSummary(result.Next, commits, parsed))
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := gitClient.CreateTag(result.Next, tagMsg.Message); err != nil {
+				return err
+			}
+			printer.Notice("✅ Created tag %s", result.Next)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&checkBreaking, "check-breaking", false, "Ask the AI whether commits not marked as breaking break compatibility")
+	cmd.Flags().BoolVar(&tag, "tag", false, "Create an annotated tag for the next version with an AI-generated message")
+
+	return cmd
+}
+
+// latestVersion returns the highest semantic version tagged in the history
+// of HEAD, or v0.0.0 and an empty tag if there is none
+func latestVersion(gitClient *git.Client) (semver.Version, string, error) {
+	tags, err := gitClient.GetTags("HEAD")
+	if err != nil {
+		return semver.Version{}, "", err
+	}
+
+	latest, latestTag := semver.Version{Prefix: "v"}, ""
+	for _, tag := range tags {
+		version, ok := semver.Parse(tag.Name)
+		if ok && (latestTag == "" || latest.Less(version)) {
+			latest, latestTag = version, tag.Name
+		}
+	}
+	return latest, latestTag, nil
+}
+
+// detectBreakingChanges asks the AI about the commits not declared breaking,
+// marks those it considers breaking in parsed and returns them
+func detectBreakingChanges(aiClient *ai.Client, gitClient *git.Client, commits []git.Commit, parsed []conventional.Commit) ([]breakingCommit, error) {
+	var input strings.Builder
+	for i, commit := range commits {
+		if parsed[i].Breaking {
+			continue
+		}
+		patch, err := gitClient.GetCommitPatch(commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&input, "### Commit %d\n%s\n\n%s\n\n", i+1, commit.Message(), git.TruncateDiff(patch, maxPatchLines, maxPatchLines))
+	}
+	if input.Len() == 0 {
+		return nil, nil
+	}
+
+	review, err := aiClient.DetectBreakingChanges(strings.TrimSpace(input.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	var detected []breakingCommit
+	for _, verdict := range review.Commits {
+		i := verdict.ID - 1
+		if !verdict.Breaking || i < 0 || i >= len(commits) || parsed[i].Breaking {
+			continue
+		}
+		parsed[i].Breaking = true
+		detected = append(detected, breakingCommit{
+			Commit:  commits[i].ShortHash(),
+			Subject: commits[i].Subject,
+			Reason:  verdict.Reason,
+		})
+	}
+	return detected, nil
+}
+
+// releaseSummary describes a release as the input for its tag message
+func releaseSummary(version string, commits []git.Commit, parsed []conventional.Commit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Version: %s\n\nCommits:\n", version)
+	for i, commit := range commits {
+		marker := ""
+		if parsed[i].Breaking {
+			marker = "[BREAKING] "
+		}
+		fmt.Fprintf(&b, "- %s%s\n", marker, commit.Subject)
+	}
+	return b.String()
+}
+
+// nextVersion is the result of the next-version command
+type nextVersion struct {
+	Current  string           `json:"current" yaml:"current"`
+	Tag      string           `json:"tag,omitempty" yaml:"tag,omitempty"`
+	Next     string           `json:"next" yaml:"next"`
+	Bump     string           `json:"bump" yaml:"bump"`
+	Commits  int              `json:"commits" yaml:"commits"`
+	Breaking []breakingCommit `json:"breaking,omitempty" yaml:"breaking,omitempty"`
+}
+
+// breakingCommit is a commit that calls for a major version
+type breakingCommit struct {
+	Commit  string `json:"commit" yaml:"commit"`
+	Subject string `json:"subject" yaml:"subject"`
+	// Reason is set for breaking changes detected by the AI
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+func (v *nextVersion) RenderText(w io.Writer) error {
+	current := v.Current
+	if v.Tag == "" {
+		current += " (no version tag)"
+	}
+	fmt.Fprintf(w, "Current version: %s\n", current)
+	fmt.Fprintf(w, "Next version:    %s (%s, %d commit(s))\n", v.Next, v.Bump, v.Commits)
+	if len(v.Breaking) > 0 {
+		fmt.Fprintln(w, "Breaking changes:")
+		for _, commit := range v.Breaking {
+			if commit.Reason != "" {
+				fmt.Fprintf(w, "  %s %s (detected: %s)\n", commit.Commit, commit.Subject, commit.Reason)
+			} else {
+				fmt.Fprintf(w, "  %s %s\n", commit.Commit, commit.Subject)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *nextVersion) RenderMarkdown(w io.Writer) error {
+	fmt.Fprintf(w, "**Next version:** `%s` (%s from `%s`, %d commit(s))\n", v.Next, v.Bump, v.Current, v.Commits)
+	if len(v.Breaking) > 0 {
+		fmt.Fprint(w, "\n**Breaking changes:**\n\n")
+		for _, commit := range v.Breaking {
+			if commit.Reason != "" {
+				fmt.Fprintf(w, "- `%s` %s: %s\n", commit.Commit, commit.Subject, commit.Reason)
+			} else {
+				fmt.Fprintf(w, "- `%s` %s\n", commit.Commit, commit.Subject)
+			}
+		}
+	}
+	return nil
+}
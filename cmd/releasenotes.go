@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/changelog"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// NewReleaseNotesCommand creates the release-notes command
+func NewReleaseNotesCommand() *cobra.Command {
+	var since string
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "release-notes",
+		Short: "Generate release notes between two tags",
+		Long: "Collect the commits and merged merge requests between the previous and the latest tag and cluster them " +
+			"into highlights, features, fixes and breaking changes, as Markdown for a GitHub or GitLab release.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			to, since, err := releaseRange(gitClient, to, since)
+			if err != nil {
+				return err
+			}
+
+			revRange := to
+			if since != "" {
+				revRange = since + ".." + to
+			}
+			commits, err := gitClient.GetCommits(revRange)
+			if err != nil {
+				return err
+			}
+			merges, err := gitClient.GetMergeCommits(revRange)
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 && len(merges) == 0 {
+				printer.Notice("No changes found in %s.", revRange)
+				return nil
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+			notes, err := aiClient.GenerateReleaseNotes(changelog.NotesInput(commits, changelog.MergedRequests(merges)))
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(notes)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "Tag of the previous release (default: the tag before --to)")
+	cmd.Flags().StringVar(&to, "to", "", "Tag or commit of the release (default: the latest tag)")
+
+	return cmd
+}
+
+// releaseRange defaults to to the latest tag and since to the tag before it.
+// since is empty when there is no earlier tag, covering the whole history.
+func releaseRange(gitClient *git.Client, to, since string) (string, string, error) {
+	tags, err := gitClient.GetTags(cmp.Or(to, "HEAD"))
+	if err != nil {
+		return "", "", err
+	}
+
+	if to == "" {
+		if len(tags) == 0 {
+			return "", "", fmt.Errorf("no tags found. Pass --to to name the release commit")
+		}
+		to = tags[0].Name
+	}
+	if since != "" {
+		return to, since, nil
+	}
+
+	commit, err := gitClient.ResolveCommit(to)
+	if err != nil {
+		return "", "", err
+	}
+	for _, tag := range tags {
+		if tag.Commit != commit {
+			return to, tag.Name, nil
+		}
+	}
+	return to, "", nil
+}
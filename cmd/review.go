@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+	"github.com/richardamare/gitai/internal/report"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewReviewCommand creates the review command
+func NewReviewCommand() *cobra.Command {
+	var sourceFlags diffSourceFlags
+	var format string
+	var reportFile string
+	var failOn string
+
+	cmd := &cobra.Command{
+		Use:   "review " + diffSourceUsage,
+		Short: "Review local changes, commits or patches",
+		Long: "Review the changes of a commit, of the commits in a range (e.g. main..feature), of a patch file (--patch) " +
+			"or of a patch read from stdin (-), such as the output of git format-patch. --staged reviews exactly what " +
+			"the next commit will contain, --worktree all changes to tracked files.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			if failOn != "" {
+				if failOn, err = models.ParseSeverity(failOn); err != nil {
+					return err
+				}
+			}
+
+			var writer report.Writer
+			if format != "" {
+				if writer, err = report.Lookup(format); err != nil {
+					return err
+				}
+			}
+
+			gitClient := git.NewClient()
+			if sourceFlags.needsRepo(args) && !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			source, err := sourceFlags.source(gitClient, args)
+			if err != nil {
+				return err
+			}
+			if source == nil {
+				return fmt.Errorf("specify a revision, a range, a patch or --staged or --worktree")
+			}
+
+			diff, err := source.Diff()
+			if err != nil {
+				return err
+			}
+			if diff == "" {
+				printer.Notice("No changes found in %s.", source)
+				return nil
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+			reviewDetails, err := aiClient.ReviewMR(diff)
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := writeReview(printer, writer, reportFile, reviewDetails); err != nil {
+				return err
+			}
+			return checkFailOn(cmd, reviewDetails, failOn)
+		},
+	}
+
+	sourceFlags.add(cmd)
+	cmd.Flags().StringVar(&format, "format", "", fmt.Sprintf("Write findings as a report (%s) instead of --output", strings.Join(report.Formats(), ", ")))
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "Write the review to this file instead of stdout")
+	cmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Exit with status %d if any finding has at least this severity (%s)", exitFindings, strings.Join(models.Severities, ", ")))
+
+	return cmd
+}
+
+// NewPreCommitHookCommand creates the hook pre-commit command
+func NewPreCommitHookCommand() *cobra.Command {
+	var failOn string
+
+	cmd := &cobra.Command{
+		Use:   "pre-commit",
+		Short: "Review staged changes and block commits with severe findings",
+		Long: "Review the staged changes and print the findings. The commit is blocked only if a finding has at least " +
+			"the severity set with --fail-on or the pre_commit.fail_on setting (default major). Failures to get a " +
+			"review are reported as warnings and do not block the commit.\n\n" +
+			"Install it with:\n\n" +
+			"  echo 'exec gitai hook pre-commit' > .git/hooks/pre-commit\n" +
+			"  chmod +x .git/hooks/pre-commit",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if failOn == "" {
+				failOn = viper.GetString("pre_commit.fail_on")
+			}
+			failOn, err := models.ParseSeverity(failOn)
+			if err != nil {
+				return err
+			}
+
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			reviewDetails, err := preCommitReview(cmd)
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  gitai: %v. Committing without a review.\n", err)
+				return nil
+			}
+			if reviewDetails == nil || len(reviewDetails.Review) == 0 {
+				return nil
+			}
+
+			if err := printer.Print(reviewDetails); err != nil {
+				return err
+			}
+			err = checkFailOn(cmd, reviewDetails, failOn)
+			var exitErr *ExitError
+			if errors.As(err, &exitErr) {
+				exitErr.Message += ". Fix them or commit with --no-verify"
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Block the commit if any finding has at least this severity (%s)", strings.Join(models.Severities, ", ")))
+
+	return cmd
+}
+
+// preCommitReview reviews the staged changes, returning nil if nothing is
+// staged
+func preCommitReview(cmd *cobra.Command) (*models.MrReviewDetails, error) {
+	diff, err := git.Staged(git.NewClient()).Diff()
+	if err != nil || diff == "" {
+		return nil, err
+	}
+
+	aiClient, err := newAIClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return aiClient.ReviewMR(diff)
+}
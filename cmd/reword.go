@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// NewRewordCommand creates the reword command
+func NewRewordCommand() *cobra.Command {
+	var base string
+	var force bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "reword [<range>]",
+		Short: "Regenerate the messages of commits on the current branch",
+		Long: "Generate a Conventional Commit message for each commit in the range from its own diff and rewrite the commits " +
+			"with the new messages, keeping their content and authors. The range defaults to the commits on the current " +
+			"branch and must end at HEAD. Commits that are already pushed or merged are refused unless --force is given.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			head, err := gitClient.GetHead()
+			if err != nil {
+				return err
+			}
+
+			var revRange, mergeBase string
+			if len(args) == 1 {
+				from, to, err := parseRange(args[0])
+				if err != nil {
+					return err
+				}
+				toCommit, err := gitClient.ResolveCommit(to)
+				if err != nil {
+					return err
+				}
+				if toCommit != head {
+					return fmt.Errorf("the range must end at HEAD")
+				}
+				revRange = from + "..HEAD"
+			} else {
+				if mergeBase, err = branchBase(gitClient, base); err != nil {
+					return err
+				}
+				revRange = mergeBase + "..HEAD"
+			}
+
+			merges, err := gitClient.GetMergeCommits(revRange)
+			if err != nil {
+				return err
+			}
+			if len(merges) > 0 {
+				return fmt.Errorf("%s contains merge commits, which cannot be reworded", revRange)
+			}
+
+			commits, err := gitClient.GetCommits(revRange)
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 {
+				return fmt.Errorf("no commits in %s", revRange)
+			}
+
+			if !force {
+				// An explicit range can reach into the base branch, so its
+				// commits are excluded in either case
+				if mergeBase == "" {
+					if mergeBase, err = branchBase(gitClient, base); err != nil {
+						return err
+					}
+				}
+				if err := checkUnpublished(gitClient, revRange, mergeBase, commits); err != nil {
+					cmd.SilenceUsage = true
+					return err
+				}
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			// Oldest commit first, in the order they are rewritten
+			plan := &rewordPlan{}
+			details := make([]*git.Commit, len(commits))
+			dryRun := false
+			for i := range commits {
+				commit, diff, err := gitClient.ShowCommit(commits[len(commits)-1-i].Hash)
+				if err != nil {
+					return err
+				}
+				details[i] = commit
+
+				message, err := aiClient.GenerateCommitMessage(diff)
+				if errors.Is(err, ai.ErrDryRun) {
+					dryRun = true
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("failed to reword %s: %w", commit.ShortHash(), err)
+				}
+				plan.Commits = append(plan.Commits, rewordEntry{
+					Commit:     commit.ShortHash(),
+					OldSubject: commit.Subject,
+					NewMessage: strings.TrimSpace(message.Message),
+				})
+			}
+			if dryRun {
+				return nil
+			}
+
+			if err := printer.Print(plan); err != nil {
+				return err
+			}
+			if !yes && !confirm(fmt.Sprintf("Rewrite these %d commits?", len(plan.Commits))) {
+				printer.Notice("Aborted, nothing was rewritten.")
+				return nil
+			}
+
+			parent := ""
+			if len(details[0].Parents) > 0 {
+				parent = details[0].Parents[0]
+			}
+			for i, commit := range details {
+				if parent, err = gitClient.CommitTree(commit, parent, plan.Commits[i].NewMessage); err != nil {
+					return err
+				}
+			}
+			if err := gitClient.UpdateHead(parent, head, "gitai reword "+revRange); err != nil {
+				return err
+			}
+			printer.Notice("✅ Reworded %d commit(s). The previous history is %s", len(details), head[:7])
+			return nil
+		},
+	}
+
+	addBaseFlag(cmd, &base)
+	cmd.Flags().BoolVar(&force, "force", false, "Also rewrite commits that are already pushed or merged")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Rewrite without asking for confirmation")
+
+	return cmd
+}
+
+// checkUnpublished returns an error listing the commits that are already on
+// a remote or reachable from mergeBase, where the branch forked from its base
+func checkUnpublished(gitClient *git.Client, revRange, mergeBase string, commits []git.Commit) error {
+	unpublished, err := gitClient.GetUnpublished(revRange, mergeBase)
+	if err != nil {
+		return err
+	}
+
+	var published []string
+	for _, commit := range commits {
+		if !unpublished[commit.Hash] {
+			published = append(published, fmt.Sprintf("  %s %s", commit.ShortHash(), commit.Subject))
+		}
+	}
+	if len(published) == 0 {
+		return nil
+	}
+	return fmt.Errorf("refusing to rewrite %d commit(s) that are already pushed or merged (use --force to rewrite them anyway):\n%s",
+		len(published), strings.Join(published, "\n"))
+}
+
+// rewordPlan is the preview of the rewritten messages
+type rewordPlan struct {
+	Commits []rewordEntry `json:"commits" yaml:"commits"`
+}
+
+// rewordEntry is the new message for a commit
+type rewordEntry struct {
+	Commit     string `json:"commit" yaml:"commit"`
+	OldSubject string `json:"oldSubject" yaml:"oldSubject"`
+	NewMessage string `json:"newMessage" yaml:"newMessage"`
+}
+
+func (p *rewordPlan) RenderText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMMIT\tOLD SUBJECT\tNEW SUBJECT")
+	for _, entry := range p.Commits {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Commit, entry.OldSubject, firstLine(entry.NewMessage))
+	}
+	return tw.Flush()
+}
+
+func (p *rewordPlan) RenderMarkdown(w io.Writer) error {
+	fmt.Fprintln(w, "| Commit | Old subject | New subject |")
+	fmt.Fprintln(w, "| --- | --- | --- |")
+	for _, entry := range p.Commits {
+		fmt.Fprintf(w, "| `%s` | %s | %s |\n", entry.Commit, escapeCell(entry.OldSubject), escapeCell(firstLine(entry.NewMessage)))
+	}
+	return nil
+}
+
+// escapeCell makes text safe to put in a Markdown table cell
+func escapeCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
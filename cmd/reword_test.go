@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+)
+
+func TestCheckUnpublishedExcludesBaseBranch(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 3, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	writeLines(t, "a.txt", 3, map[int]string{1: "on main"})
+	runGit(t, "commit", "--quiet", "-am", "main change")
+	runGit(t, "switch", "--quiet", "-c", "feature")
+	writeLines(t, "a.txt", 3, map[int]string{1: "on main", 2: "on feature"})
+	runGit(t, "commit", "--quiet", "-am", "feature change")
+
+	gitClient := git.NewClient()
+	mergeBase, err := branchBase(gitClient, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An explicit range that reaches into main
+	commits, err := gitClient.GetCommits("HEAD~2..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkUnpublished(gitClient, "HEAD~2..HEAD", mergeBase, commits)
+	if err == nil || !strings.Contains(err.Error(), "main change") || strings.Contains(err.Error(), "feature change") {
+		t.Errorf("checkUnpublished() = %v, want only the main commit refused", err)
+	}
+
+	commits, err = gitClient.GetCommits(mergeBase + "..HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkUnpublished(gitClient, mergeBase+"..HEAD", mergeBase, commits); err != nil {
+		t.Errorf("checkUnpublished() on the branch commits = %v", err)
+	}
+}
@@ -1,39 +1,112 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/richardamare/gitai/internal/models"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var rootCmd = &cobra.Command{
-    Use:   "gitai",
-    Short: "AI-powered Git CLI tool",
-    Long:  "A CLI tool that uses AI to generate commit messages, PR descriptions, and code reviews",
+	Use:   "gitai",
+	Short: "AI-powered Git CLI tool",
+	Long:  "A CLI tool that uses AI to generate commit messages, PR descriptions, and code reviews",
+}
+
+// ExitError makes Execute exit with Code instead of the default status 1
+type ExitError struct {
+	Code    int
+	Message string
+}
+
+func (e *ExitError) Error() string {
+	return e.Message
 }
 
 // Execute runs the root command
 func Execute() {
-    if err := rootCmd.Execute(); err != nil {
-        os.Exit(1)
-    }
+	err := rootCmd.Execute()
+	// Printed here rather than in a post-run hook, which cobra skips when
+	// the command fails
+	printUsageSummary()
+	if err != nil {
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		os.Exit(1)
+	}
 }
 
 func init() {
-    cobra.OnInitialize(initConfig)
-    
-    // Add subcommands
-    rootCmd.AddCommand(NewCommitCommand())
-    rootCmd.AddCommand(NewMRCommand())
-    rootCmd.AddCommand(NewVersionCommand())
-    // Add other commands here: PR, review, etc.
+	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Always call the AI provider instead of reusing cached responses")
+	viper.BindPFlag("no_cache", rootCmd.PersistentFlags().Lookup("no-cache"))
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Print the requests that would be sent to the AI provider without sending them")
+	viper.BindPFlag("dry_run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	rootCmd.PersistentFlags().String("dry-run-format", "text", "Format of the --dry-run output: text or json")
+	viper.BindPFlag("dry_run_format", rootCmd.PersistentFlags().Lookup("dry-run-format"))
+	rootCmd.PersistentFlags().StringP("output", "o", "text", "Output format: text, json, yaml or markdown")
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Print token usage and cost after AI requests")
+	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+
+	// Add subcommands
+	rootCmd.AddCommand(NewCommitCommand())
+	rootCmd.AddCommand(NewSplitCommand())
+	rootCmd.AddCommand(NewAbsorbCommand())
+	rootCmd.AddCommand(NewRewordCommand())
+	rootCmd.AddCommand(NewSquashMsgCommand())
+	rootCmd.AddCommand(NewBranchCommand())
+	rootCmd.AddCommand(NewReviewCommand())
+	rootCmd.AddCommand(NewMRCommand())
+	rootCmd.AddCommand(NewCICommand())
+	rootCmd.AddCommand(NewChangelogCommand())
+	rootCmd.AddCommand(NewReleaseNotesCommand())
+	rootCmd.AddCommand(NewNextVersionCommand())
+	rootCmd.AddCommand(NewExplainCommand())
+	rootCmd.AddCommand(NewWhyCommand())
+	rootCmd.AddCommand(NewHookCommand())
+	rootCmd.AddCommand(NewCacheCommand())
+	rootCmd.AddCommand(NewUsageCommand())
+	rootCmd.AddCommand(NewVersionCommand())
+	// Add other commands here: PR, review, etc.
 }
 
 func initConfig() {
-    viper.AutomaticEnv()
-    viper.SetEnvPrefix("GITAI")
-    
-    // Bind environment variables
-    viper.BindEnv("openai_api_key", "OPENAI_API_KEY")
-}
\ No newline at end of file
+	viper.AutomaticEnv()
+	viper.SetEnvPrefix("GITAI")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	// Bind environment variables
+	viper.BindEnv("openai_api_key", "OPENAI_API_KEY")
+	viper.BindEnv("gitlab.token", "GITAI_GITLAB_TOKEN", "GITLAB_TOKEN")
+	viper.BindEnv("github.token", "GITAI_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN")
+	viper.BindEnv("gitea.token", "GITAI_GITEA_TOKEN", "GITEA_TOKEN")
+	viper.BindEnv("bitbucket.token", "GITAI_BITBUCKET_TOKEN", "BITBUCKET_TOKEN")
+
+	viper.SetDefault("cache.ttl", "168h")
+	viper.SetDefault("cache.max_size", "50MB")
+	viper.SetDefault("redact.enabled", true)
+	viper.SetDefault("remote", "origin")
+	viper.SetDefault("branch.pattern", "{type}/{ticket}-{slug}")
+	viper.SetDefault("pre_commit.fail_on", models.SeverityMajor)
+
+	// Optional config file in the user config dir, e.g. ~/.config/gitai/config.yaml
+	viper.SetConfigName("config")
+	if dir, err := os.UserConfigDir(); err == nil {
+		viper.AddConfigPath(filepath.Join(dir, "gitai"))
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read config file: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// diffSourceUsage documents the positional argument read by diffSourceFlags
+const diffSourceUsage = "[<rev> | <from>..<to> | -]"
+
+// diffSourceFlags select the changes a command works on instead of its
+// default, from a positional argument or flags
+type diffSourceFlags struct {
+	staged   bool
+	worktree bool
+	patch    string
+}
+
+// add registers the flags
+func (f *diffSourceFlags) add(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.staged, "staged", false, "Use the staged changes")
+	cmd.Flags().BoolVar(&f.worktree, "worktree", false, "Use the staged and unstaged changes to tracked files")
+	cmd.Flags().StringVar(&f.patch, "patch", "", "Use the changes in a patch file written by git diff or git format-patch")
+}
+
+// source returns the diff source selected by args and the flags: a commit,
+// a range of commits, a patch read from stdin ("-"), the staged or working
+// tree changes or a patch file. It returns nil if none is selected.
+func (f *diffSourceFlags) source(gitClient *git.Client, args []string) (git.DiffSource, error) {
+	var sources []git.DiffSource
+	if len(args) == 1 {
+		switch arg := args[0]; {
+		case arg == "-":
+			sources = append(sources, git.PatchReader("stdin", os.Stdin))
+		case strings.Contains(arg, ".."):
+			from, to, err := parseRange(arg)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, git.Range(gitClient, from, to))
+		default:
+			sources = append(sources, git.Revision(gitClient, arg))
+		}
+	}
+	if f.staged {
+		sources = append(sources, git.Staged(gitClient))
+	}
+	if f.worktree {
+		sources = append(sources, git.Worktree(gitClient))
+	}
+	if f.patch != "" {
+		sources = append(sources, git.PatchFile(f.patch))
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, nil
+	case 1:
+		return sources[0], nil
+	default:
+		return nil, fmt.Errorf("use only one of a revision, a range, -, --staged, --worktree and --patch")
+	}
+}
+
+// needsRepo reports whether the selected changes are read from the
+// repository rather than a patch
+func (f *diffSourceFlags) needsRepo(args []string) bool {
+	return f.patch == "" && (len(args) == 0 || args[0] != "-")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+)
+
+func TestWorktreeSourceBeforeFirstCommit(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 3, nil)
+	writeLines(t, "untracked.txt", 3, nil)
+	runGit(t, "add", "a.txt")
+
+	f := &diffSourceFlags{worktree: true}
+	source, err := f.source(git.NewClient(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := source.Diff()
+	if err != nil {
+		t.Fatalf("Diff() on an unborn branch = %v", err)
+	}
+	if !strings.Contains(diff, "+a.txt line 1") || strings.Contains(diff, "untracked.txt") {
+		t.Errorf("Diff() =\n%s", diff)
+	}
+	if !strings.Contains(source.String(), "untracked files are not considered") {
+		t.Errorf("String() = %q, want a note on untracked files", source)
+	}
+}
+
+func TestPatchSourceReadsFormatPatch(t *testing.T) {
+	dir := tempRepo(t)
+	// Removed and added lines that look like --- and +++ headers
+	writeLines(t, "a.txt", 20, map[int]string{3: "-- old", 15: "++ old"})
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+	writeLines(t, "a.txt", 20, map[int]string{3: "++ new", 15: "-- new"})
+	runGit(t, "commit", "--quiet", "-am", "change a\n\nThe message quotes a hunk:\n\n--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@")
+	writeLines(t, "b.txt", 3, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "add b")
+
+	patch := filepath.Join(dir, "series.patch")
+	if err := os.WriteFile(patch, []byte(runGit(t, "format-patch", "--stdout", "HEAD~2")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &diffSourceFlags{patch: patch}
+	source, err := f.source(git.NewClient(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff, err := source.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := runGit(t, "show", "--format=", "--patch", "HEAD~1") + "\n" + runGit(t, "show", "--format=", "--patch", "HEAD")
+	if diff != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func TestPatchSourceRejectsPlainDiff(t *testing.T) {
+	plain := "Index: a.txt\n" +
+		"===================================================================\n" +
+		"--- a.txt\t(revision 12)\n" +
+		"+++ a.txt\t(working copy)\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" first\n" +
+		"-second\n" +
+		"+changed\n"
+
+	_, err := git.PatchReader("stdin", strings.NewReader(plain)).Diff()
+	if err == nil || !strings.Contains(err.Error(), "no diff --git headers") {
+		t.Errorf("Diff() of a plain diff = %v, want an error", err)
+	}
+}
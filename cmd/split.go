@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// NewSplitCommand creates the split command
+func NewSplitCommand() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split staged changes into multiple atomic commits",
+		Long: "Group the hunks of the staged changes into logical commits with AI, show the plan for confirmation and create " +
+			"the commits one after another. If anything fails, the original index and HEAD are restored.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			patch, err := gitClient.GetStagedPatch()
+			if err != nil {
+				return err
+			}
+			files := git.SplitDiff(patch)
+			if len(files) == 0 {
+				return fmt.Errorf("no staged changes found")
+			}
+
+			units := splitUnits(files)
+			if len(units) == 1 {
+				printer.Notice("The staged changes are a single hunk and cannot be split. Use gitai commit instead.")
+				return nil
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+			plan, err := aiClient.PlanSplit(describeUnits(files, units))
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			groups, err := resolvePlan(plan, files, units)
+			if err != nil {
+				return err
+			}
+			if err := printer.Print(&splitView{Commits: groups}); err != nil {
+				return err
+			}
+
+			if !yes && !confirm(fmt.Sprintf("Create these %d commits?", len(groups))) {
+				printer.Notice("Aborted, nothing was committed.")
+				return nil
+			}
+
+			return commitGroups(printer.Notice, gitClient, files, units, groups, nil)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create the commits without asking for confirmation")
+
+	return cmd
+}
+
+// splitUnit is the smallest part of the staged changes that can be
+// committed on its own: a single hunk, or a whole file diff that cannot be
+// split
+type splitUnit struct {
+	file int
+	// hunk is the index of the hunk in the file, or -1 for the whole file
+	hunk int
+}
+
+// splitGroup is a planned commit of units
+type splitGroup struct {
+	Message string   `json:"message" yaml:"message"`
+	Hunks   []string `json:"hunks" yaml:"hunks"`
+	units   []int
+}
+
+// splitUnits breaks the file diffs into units
+func splitUnits(files []git.FileDiff) []splitUnit {
+	var units []splitUnit
+	for i, file := range files {
+		if file.Atomic() {
+			units = append(units, splitUnit{file: i, hunk: -1})
+			continue
+		}
+		for j := range file.Hunks {
+			units = append(units, splitUnit{file: i, hunk: j})
+		}
+	}
+	return units
+}
+
+// describeUnits lists the numbered units as the input for planning
+func describeUnits(files []git.FileDiff, units []splitUnit) string {
+	var b strings.Builder
+	for i, unit := range units {
+		file := files[unit.file]
+		text := file.Text
+		if unit.hunk >= 0 {
+			text = file.Hunks[unit.hunk].Text()
+		}
+		if i := strings.Index(text, "\nGIT binary patch"); i >= 0 {
+			text = text[:i] + "\n(binary content)"
+		}
+		fmt.Fprintf(&b, "### Hunk %d: %s\n%s\n", i+1, file.Path(), strings.TrimRight(text, "\n"))
+	}
+	return b.String()
+}
+
+// unitLabel names a unit for display, e.g. "main.go @@ -1,3 +1,4 @@"
+func unitLabel(files []git.FileDiff, unit splitUnit) string {
+	file := files[unit.file]
+	if unit.hunk < 0 {
+		return file.Path()
+	}
+	return file.Path() + " " + file.Hunks[unit.hunk].Header
+}
+
+// resolvePlan turns the AI's plan into groups of units. Hunks listed twice
+// stay in their first commit; hunks left out join a commit touching the same
+// file, or the last commit.
+func resolvePlan(plan *models.SplitPlan, files []git.FileDiff, units []splitUnit) ([]splitGroup, error) {
+	owner := make([]int, len(units))
+	for i := range owner {
+		owner[i] = -1
+	}
+
+	var groups []splitGroup
+	for _, commit := range plan.Commits {
+		group := splitGroup{Message: strings.TrimSpace(commit.Message)}
+		for _, id := range commit.Hunks {
+			if i := id - 1; i >= 0 && i < len(units) && owner[i] < 0 {
+				owner[i] = len(groups)
+				group.units = append(group.units, i)
+			}
+		}
+		if len(group.units) == 0 {
+			continue
+		}
+		if group.Message == "" {
+			return nil, fmt.Errorf("the AI planned a commit without a message")
+		}
+		groups = append(groups, group)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("the AI did not plan any commits")
+	}
+
+	for i, unit := range units {
+		if owner[i] >= 0 {
+			continue
+		}
+		target := len(groups) - 1
+		for j := range units {
+			if owner[j] >= 0 && units[j].file == unit.file {
+				target = owner[j]
+				break
+			}
+		}
+		owner[i] = target
+		groups[target].units = append(groups[target].units, i)
+	}
+
+	for i := range groups {
+		sort.Ints(groups[i].units)
+		for _, unit := range groups[i].units {
+			groups[i].Hunks = append(groups[i].Hunks, unitLabel(files, units[unit]))
+		}
+	}
+	return groups, nil
+}
+
+// groupPatch builds the patch applying the units of group
+func groupPatch(files []git.FileDiff, units []splitUnit, group splitGroup) string {
+	hunks := map[int][]int{}
+	whole := map[int]bool{}
+	var order []int
+	for _, i := range group.units {
+		unit := units[i]
+		if _, seen := hunks[unit.file]; !seen && !whole[unit.file] {
+			order = append(order, unit.file)
+		}
+		if unit.hunk < 0 {
+			whole[unit.file] = true
+		} else {
+			hunks[unit.file] = append(hunks[unit.file], unit.hunk)
+		}
+	}
+
+	var b strings.Builder
+	for _, i := range order {
+		if whole[i] {
+			b.WriteString(strings.TrimRight(files[i].Text, "\n") + "\n")
+		} else {
+			b.WriteString(files[i].Patch(hunks[i]))
+		}
+	}
+	return b.String()
+}
+
+// commitGroups unstages everything, commits the groups one by one and
+// stages the keep units again. On failure HEAD and the index are reset to
+// their original state.
+func commitGroups(notice func(string, ...any), gitClient *git.Client, files []git.FileDiff, units []splitUnit, groups []splitGroup, keep []int) error {
+	head, err := gitClient.GetHead()
+	if err != nil {
+		return fmt.Errorf("this command needs at least one existing commit: %w", err)
+	}
+	tree, err := gitClient.WriteIndexTree()
+	if err != nil {
+		return err
+	}
+
+	rollback := func(cause error) error {
+		if err := errors.Join(gitClient.ResetSoft(head), gitClient.RestoreIndex(tree)); err != nil {
+			return fmt.Errorf("%w; restoring the original state failed too (HEAD was %s, index tree %s): %v", cause, head, tree, err)
+		}
+		return fmt.Errorf("%w; the original index and HEAD were restored", cause)
+	}
+
+	if err := gitClient.ResetIndex(); err != nil {
+		return rollback(err)
+	}
+	for i, group := range groups {
+		if err := gitClient.ApplyToIndex(groupPatch(files, units, group)); err != nil {
+			return rollback(fmt.Errorf("commit %d: %w", i+1, err))
+		}
+		// Hooks would run once per commit and could rewrite fixup! subjects;
+		// the result is checked against the staged tree below instead
+		if err := gitClient.CommitIndex(group.Message); err != nil {
+			return rollback(fmt.Errorf("commit %d: %w", i+1, err))
+		}
+		notice("✅ Committed %d/%d: %s", i+1, len(groups), firstLine(group.Message))
+	}
+	if len(keep) > 0 {
+		if err := gitClient.ApplyToIndex(groupPatch(files, units, splitGroup{units: keep})); err != nil {
+			return rollback(fmt.Errorf("restaging remaining changes: %w", err))
+		}
+	}
+
+	// The commits and kept changes together must reproduce exactly what was staged
+	final, err := gitClient.WriteIndexTree()
+	if err != nil {
+		return rollback(err)
+	}
+	if final != tree {
+		return rollback(fmt.Errorf("the created commits do not match the staged changes"))
+	}
+	return nil
+}
+
+// splitView is the plan shown before committing
+type splitView struct {
+	Commits []splitGroup `json:"commits" yaml:"commits"`
+}
+
+func (v *splitView) RenderText(w io.Writer) error {
+	for i, group := range v.Commits {
+		fmt.Fprintf(w, "Commit %d: %s\n", i+1, firstLine(group.Message))
+		for _, hunk := range group.Hunks {
+			fmt.Fprintf(w, "  %s\n", hunk)
+		}
+	}
+	return nil
+}
+
+func (v *splitView) RenderMarkdown(w io.Writer) error {
+	for i, group := range v.Commits {
+		fmt.Fprintf(w, "%d. `%s`\n", i+1, firstLine(group.Message))
+		for _, hunk := range group.Hunks {
+			fmt.Fprintf(w, "   - `%s`\n", hunk)
+		}
+	}
+	return nil
+}
+
+// firstLine returns text up to the first newline
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
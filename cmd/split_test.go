@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// tempRepo creates an empty repository with an isolated git configuration
+// and makes it the working directory for the rest of the test
+func tempRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(dir, ".gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	runGit(t, "init", "--quiet", "--initial-branch=main")
+	return dir
+}
+
+// runGit runs git in the working directory and returns its trimmed output
+func runGit(t *testing.T, args ...string) string {
+	t.Helper()
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// writeLines writes a file of numbered lines, replacing the lines in changes
+func writeLines(t *testing.T, name string, count int, changes map[int]string) {
+	t.Helper()
+	var b strings.Builder
+	for i := 1; i <= count; i++ {
+		if line, ok := changes[i]; ok {
+			b.WriteString(line + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "%s line %d\n", name, i)
+	}
+	if err := os.WriteFile(name, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stageTwoFileChange commits two files and stages two separate hunks in each
+func stageTwoFileChange(t *testing.T) (*git.Client, []git.FileDiff, []splitUnit) {
+	t.Helper()
+	writeLines(t, "a.txt", 40, nil)
+	writeLines(t, "b.txt", 40, nil)
+	runGit(t, "add", ".")
+	runGit(t, "commit", "--quiet", "-m", "initial")
+
+	writeLines(t, "a.txt", 40, map[int]string{2: "a early", 35: "a late"})
+	writeLines(t, "b.txt", 40, map[int]string{3: "b early", 30: "b late"})
+	runGit(t, "add", ".")
+
+	gitClient := git.NewClient()
+	patch, err := gitClient.GetStagedPatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := git.SplitDiff(patch)
+	units := splitUnits(files)
+	if len(files) != 2 || len(units) != 4 {
+		t.Fatalf("got %d files and %d units, want 2 and 4", len(files), len(units))
+	}
+	return gitClient, files, units
+}
+
+func TestCommitGroupsAppliesHunksOutOfOrder(t *testing.T) {
+	tempRepo(t)
+	gitClient, files, units := stageTwoFileChange(t)
+	staged := runGit(t, "write-tree")
+
+	// Units are numbered a.txt#1, a.txt#2, b.txt#1, b.txt#2; every commit
+	// takes a later hunk before an earlier one of the same file
+	plan := &models.SplitPlan{Commits: []models.SplitCommit{
+		{Message: "second hunks", Hunks: []int{4, 2}},
+		{Message: "first hunk of b", Hunks: []int{3}},
+		{Message: "first hunk of a", Hunks: []int{1}},
+	}}
+	groups, err := resolvePlan(plan, files, units)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := commitGroups(func(string, ...any) {}, gitClient, files, units, groups, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if tree := runGit(t, "rev-parse", "HEAD^{tree}"); tree != staged {
+		t.Errorf("HEAD tree = %s, want the staged tree %s", tree, staged)
+	}
+	if subjects := runGit(t, "log", "--format=%s"); subjects != "first hunk of a\nfirst hunk of b\nsecond hunks\ninitial" {
+		t.Errorf("commits =\n%s", subjects)
+	}
+	if diff := runGit(t, "diff", "--cached", "--name-only"); diff != "" {
+		t.Errorf("changes left staged: %s", diff)
+	}
+
+	// The first commit holds exactly the later hunks
+	first := runGit(t, "show", "--format=", "-U0", "HEAD~2")
+	for _, want := range []string{"+a late", "+b late"} {
+		if !strings.Contains(first, want) {
+			t.Errorf("first commit lacks %q:\n%s", want, first)
+		}
+	}
+	for _, unwanted := range []string{"a early", "b early"} {
+		if strings.Contains(first, unwanted) {
+			t.Errorf("first commit contains %q:\n%s", unwanted, first)
+		}
+	}
+}
+
+func TestCommitGroupsKeepsRemainingHunksStaged(t *testing.T) {
+	tempRepo(t)
+	gitClient, files, units := stageTwoFileChange(t)
+	staged := runGit(t, "write-tree")
+	head := runGit(t, "rev-parse", "HEAD")
+
+	groups := []splitGroup{{Message: "part", units: []int{3, 0}}}
+	if err := commitGroups(func(string, ...any) {}, gitClient, files, units, groups, []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if index := runGit(t, "write-tree"); index != staged {
+		t.Errorf("index tree = %s, want the originally staged tree %s", index, staged)
+	}
+	if parent := runGit(t, "rev-parse", "HEAD~1"); parent != head {
+		t.Errorf("HEAD~1 = %s, want %s", parent, head)
+	}
+	if kept := runGit(t, "diff", "--cached", "-U0"); !strings.Contains(kept, "+a late") || !strings.Contains(kept, "+b early") {
+		t.Errorf("kept hunks not staged:\n%s", kept)
+	}
+}
+
+func TestCommitGroupsNeedsACommit(t *testing.T) {
+	tempRepo(t)
+	writeLines(t, "a.txt", 3, nil)
+	runGit(t, "add", ".")
+
+	err := commitGroups(func(string, ...any) {}, git.NewClient(), nil, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "needs at least one existing commit") || strings.Contains(err.Error(), "split") {
+		t.Errorf("commitGroups() on an unborn branch = %v", err)
+	}
+}
+
+func TestCommitGroupsSkipsHooks(t *testing.T) {
+	dir := tempRepo(t)
+	gitClient, files, units := stageTwoFileChange(t)
+
+	hooks := filepath.Join(dir, ".git", "hooks")
+	if err := os.MkdirAll(hooks, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, script := range map[string]string{
+		"pre-commit": "#!/bin/sh\nexit 1\n",
+		"commit-msg": "#!/bin/sh\necho rewritten > \"$1\"\n",
+	} {
+		if err := os.WriteFile(filepath.Join(hooks, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groups := []splitGroup{{Message: "fixup! initial", units: []int{0, 1, 2, 3}}}
+	if err := commitGroups(func(string, ...any) {}, gitClient, files, units, groups, nil); err != nil {
+		t.Fatal(err)
+	}
+	if subject := runGit(t, "log", "-1", "--format=%s"); subject != "fixup! initial" {
+		t.Errorf("subject = %q, want the planned message", subject)
+	}
+}
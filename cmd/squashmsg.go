@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// Limits for the diff sent with the commit messages of a branch or range,
+// which can span many commits
+const (
+	squashDiffFileLines = 200
+	squashDiffLines     = 1500
+)
+
+// NewSquashMsgCommand creates the squash-msg command
+func NewSquashMsgCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "squash-msg [<base>]",
+		Short: "Generate one commit message for squashing the current branch",
+		Long: "Generate a single Conventional Commit message for the current branch from its diff since the merge base " +
+			"with base and the messages of its commits. Base defaults to the default branch of the configured remote.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			base := ""
+			if len(args) == 1 {
+				base = args[0]
+			}
+			mergeBase, err := branchBase(gitClient, base)
+			if err != nil {
+				return err
+			}
+			commits, err := gitClient.GetCommits(mergeBase + "..HEAD")
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 {
+				return fmt.Errorf("no commits on the current branch since %s", mergeBase)
+			}
+			diff, err := gitClient.GetDiffBetween(mergeBase, "HEAD")
+			if err != nil {
+				return err
+			}
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			commitMsg, err := aiClient.GenerateSquashMessage(squashInput(commitMessages(commits), diff))
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(commitMsg)
+		},
+	}
+
+	return cmd
+}
+
+// commitMessages lists the messages of commits oldest first
+func commitMessages(commits []git.Commit) string {
+	var b strings.Builder
+	for i := len(commits) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "### %s\n%s\n\n", commits[i].ShortHash(), commits[i].Message())
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// squashInput combines commit messages and the diff they add up to, cut
+// down to squashDiffLines, as the input for generating a squash message
+func squashInput(messages, diff string) string {
+	return fmt.Sprintf("## Commits\n\n%s\n\n## Diff\n\n%s", messages, git.TruncateDiff(diff, squashDiffFileLines, squashDiffLines))
+}
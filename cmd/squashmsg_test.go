@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSquashInputCapsDiff(t *testing.T) {
+	var diff strings.Builder
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&diff, "diff --git a/f%d.txt b/f%d.txt\n--- a/f%d.txt\n+++ b/f%d.txt\n@@ -1,0 +1,500 @@\n", i, i, i, i)
+		for j := 0; j < 500; j++ {
+			fmt.Fprintf(&diff, "+line %d\n", j)
+		}
+	}
+
+	input := squashInput("### abc1234\nfeat: add files", diff.String())
+	if lines := strings.Count(input, "\n"); lines > squashDiffLines+20 {
+		t.Errorf("input has %d lines, want about %d", lines, squashDiffLines)
+	}
+	if !strings.Contains(input, "more lines of f0.txt left out") || !strings.Contains(input, "f19.txt") {
+		t.Errorf("input does not note what was left out:\n%s", input[len(input)-500:])
+	}
+	if !strings.HasPrefix(input, "## Commits\n\n### abc1234\nfeat: add files\n\n## Diff\n\ndiff --git a/f0.txt b/f0.txt") {
+		t.Errorf("input starts with %q", input[:100])
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/richardamare/gitai/internal/usage"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewUsageCommand creates the usage command
+func NewUsageCommand() *cobra.Command {
+	var groupBy []string
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Report AI token usage and cost",
+		Long:  "Report token usage and cost recorded in the local usage ledger, grouped by day, command, repo and/or model.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			for _, dimension := range groupBy {
+				if _, ok := usage.Dimensions[dimension]; !ok {
+					return fmt.Errorf("unknown grouping %q: use day, command, repo or model", dimension)
+				}
+			}
+
+			ledger, err := newLedger()
+			if err != nil {
+				return err
+			}
+
+			all, err := ledger.Records()
+			if err != nil {
+				return err
+			}
+
+			records := all
+			if since != "" {
+				start, err := time.ParseInLocation(time.DateOnly, since, time.Local)
+				if err != nil {
+					return fmt.Errorf("invalid --since date %q, expected YYYY-MM-DD: %w", since, err)
+				}
+				records = usage.Since(records, start)
+			}
+
+			if len(records) == 0 && !printer.Structured() {
+				printer.Notice("No usage recorded.")
+				return nil
+			}
+
+			report := usageReport{
+				GroupBy: groupBy,
+				Groups:  usage.Summarize(records, groupBy),
+				Total:   usage.Group{Key: "TOTAL"},
+			}
+			for _, group := range report.Groups {
+				report.Total.Requests += group.Requests
+				report.Total.PromptTokens += group.PromptTokens
+				report.Total.CompletionTokens += group.CompletionTokens
+				report.Total.Cost += group.Cost
+			}
+			if budget := viper.GetFloat64("budget.monthly"); budget > 0 {
+				report.Budget = &budgetStatus{
+					Monthly: budget,
+					Spent:   usage.MonthToDate(all, time.Now()),
+				}
+			}
+
+			return printer.Print(report)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&groupBy, "by", []string{"day"}, "Group by day, command, repo and/or model (comma-separated)")
+	cmd.Flags().StringVar(&since, "since", "", "Only include usage on or after this date (YYYY-MM-DD)")
+
+	return cmd
+}
+
+// usageReport is the result of the usage command
+type usageReport struct {
+	GroupBy []string      `json:"groupBy" yaml:"groupBy"`
+	Groups  []usage.Group `json:"groups" yaml:"groups"`
+	Total   usage.Group   `json:"total" yaml:"total"`
+	Budget  *budgetStatus `json:"budget,omitempty" yaml:"budget,omitempty"`
+}
+
+// budgetStatus is the month-to-date spend against the configured budget
+type budgetStatus struct {
+	Monthly float64 `json:"monthly" yaml:"monthly"`
+	Spent   float64 `json:"spent" yaml:"spent"`
+}
+
+func (r usageReport) RenderText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tREQUESTS\tPROMPT\tCOMPLETION\tCOST\n", strings.ToUpper(strings.Join(r.GroupBy, " / ")))
+	for _, group := range append(r.Groups, r.Total) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t$%.4f\n", group.Key, group.Requests, group.PromptTokens, group.CompletionTokens, group.Cost)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if r.Budget != nil {
+		fmt.Fprintf(w, "\nMonthly budget: $%.2f of $%.2f spent\n", r.Budget.Spent, r.Budget.Monthly)
+	}
+	return nil
+}
+
+func (r usageReport) RenderMarkdown(w io.Writer) error {
+	fmt.Fprintf(w, "| %s | Requests | Prompt tokens | Completion tokens | Cost |\n", strings.Join(r.GroupBy, " / "))
+	fmt.Fprintln(w, "|---|---:|---:|---:|---:|")
+	for _, group := range r.Groups {
+		fmt.Fprintf(w, "| %s | %d | %d | %d | $%.4f |\n", group.Key, group.Requests, group.PromptTokens, group.CompletionTokens, group.Cost)
+	}
+	fmt.Fprintf(w, "| **Total** | %d | %d | %d | $%.4f |\n", r.Total.Requests, r.Total.PromptTokens, r.Total.CompletionTokens, r.Total.Cost)
+
+	if r.Budget != nil {
+		fmt.Fprintf(w, "\nMonthly budget: $%.2f of $%.2f spent\n", r.Budget.Spent, r.Budget.Monthly)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 )
@@ -13,8 +14,27 @@ func NewVersionCommand() *cobra.Command {
 		Use:   "version",
 		Short: "Print the version number of Git AI CLI",
 		Long:  `All software has versions. This is Git AI CLI's.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("GitAI CLI Version: %s\n", AppVersion)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+			return printer.Print(versionInfo{Version: AppVersion})
 		},
 	}
-} 
\ No newline at end of file
+}
+
+// versionInfo is the result of the version command
+type versionInfo struct {
+	Version string `json:"version" yaml:"version"`
+}
+
+func (v versionInfo) RenderText(w io.Writer) error {
+	fmt.Fprintf(w, "GitAI CLI Version: %s\n", v.Version)
+	return nil
+}
+
+func (v versionInfo) RenderMarkdown(w io.Writer) error {
+	fmt.Fprintf(w, "**GitAI CLI Version:** `%s`\n", v.Version)
+	return nil
+}
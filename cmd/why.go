@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/ai"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// NewWhyCommand creates the why command
+func NewWhyCommand() *cobra.Command {
+	var rev string
+	var maxCommits int
+
+	cmd := &cobra.Command{
+		Use:   "why <file>:<line>[-<end>]",
+		Short: "Explain why lines of code are there from their history",
+		Long: "Collect the commits that introduced and changed a line or range of lines with git blame and git log -L, " +
+			"following renames, and explain the history and rationale of the code. Line numbers refer to the file at " +
+			"--rev. When more than --max-commits commits changed the lines, the newest ones and the one that introduced " +
+			"the lines are used.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, start, end, err := parseLocation(args[0])
+			if err != nil {
+				return err
+			}
+			if maxCommits < 1 {
+				return fmt.Errorf("--max-commits must be at least 1")
+			}
+
+			printer, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			gitClient := git.NewClient()
+			if !gitClient.IsGitRepo() {
+				return fmt.Errorf("not in a git repository")
+			}
+
+			blame, err := gitClient.BlameRange(rev, path, start, end)
+			if err != nil {
+				return err
+			}
+			history, err := gitClient.GetLineHistory(rev, path, start, end)
+			if err != nil {
+				return err
+			}
+			if len(history) == 0 {
+				return fmt.Errorf("no commits changed %s", args[0])
+			}
+			history, omitted := capHistory(history, maxCommits)
+
+			aiClient, err := newAIClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			lineHistory, err := aiClient.NarrateLineHistory(whyInput(path, rev, blame, history, omitted))
+			if errors.Is(err, ai.ErrDryRun) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			return printer.Print(lineHistory)
+		},
+	}
+
+	cmd.Flags().StringVar(&rev, "rev", "HEAD", "Revision the line numbers refer to")
+	cmd.Flags().IntVar(&maxCommits, "max-commits", 10, "Maximum number of commits to include")
+
+	return cmd
+}
+
+// parseLocation parses "<file>:<line>" and "<file>:<start>-<end>"
+func parseLocation(arg string) (string, int, int, error) {
+	i := strings.LastIndex(arg, ":")
+	if i <= 0 {
+		return "", 0, 0, fmt.Errorf("invalid location %q, expected <file>:<line> or <file>:<start>-<end>", arg)
+	}
+	path, lines := arg[:i], arg[i+1:]
+
+	first, last, isRange := strings.Cut(lines, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 1 {
+		return "", 0, 0, fmt.Errorf("invalid line %q in %q", first, arg)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return "", 0, 0, fmt.Errorf("invalid line range %q in %q", lines, arg)
+		}
+	}
+	return path, start, end, nil
+}
+
+// capHistory keeps at most max commits of history, which is newest first:
+// the newest ones and the oldest, which introduced the lines. It returns
+// the number of commits left out.
+func capHistory(history []git.LineChange, max int) ([]git.LineChange, int) {
+	if len(history) <= max {
+		return history, 0
+	}
+	capped := append(history[:max-1:max-1], history[len(history)-1])
+	return capped, len(history) - max
+}
+
+// whyInput describes the lines and their history for the AI
+func whyInput(path, rev string, blame []git.BlameLine, history []git.LineChange, omitted int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Lines of %s at %s\n\n", path, rev)
+	for _, line := range blame {
+		fmt.Fprintf(&b, "%d (%.7s): %s\n", line.Number, line.Commit, line.Text)
+	}
+
+	fmt.Fprint(&b, "\n## Commits, newest first\n")
+	for i, change := range history {
+		if omitted > 0 && i == len(history)-1 {
+			fmt.Fprintf(&b, "\n(%d intermediate commits left out)\n", omitted)
+		}
+		fmt.Fprintf(&b, "\n### %s by %s on %s\n\n%s\n\n%s\n",
+			change.ShortHash(), change.AuthorName, change.AuthorDate, change.Message(), change.Diff)
+	}
+	return strings.TrimSpace(b.String())
+}
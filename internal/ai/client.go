@@ -3,189 +3,294 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/richardamare/gitai/internal/cache"
+	"github.com/richardamare/gitai/internal/git"
 	"github.com/richardamare/gitai/internal/models"
+	"github.com/richardamare/gitai/internal/redact"
+	"github.com/richardamare/gitai/internal/usage"
 	"github.com/sashabaranov/go-openai"
 )
 
-const model = openai.GPT4oMini
+const (
+	provider    = "openai"
+	model       = openai.GPT4oMini
+	temperature = 0.3
+)
+
+// Limits for the diff sent alongside the file summaries when generating
+// merge request details
+const (
+	detailsDiffFileLines = 200
+	detailsDiffLines     = 1500
+)
+
+// ErrDryRun is returned instead of a response when the client only prints
+// the requests it would send
+var ErrDryRun = errors.New("dry run: request not sent")
 
 // Client handles AI operations
 type Client struct {
-    client *openai.Client
+	client   *openai.Client
+	model    string
+	cache    *cache.Cache
+	usage    *usage.Tracker
+	redactor *redact.Redactor
+	onRedact func([]redact.Finding)
+	dryRun   func(Request) error
+}
+
+// Request is the payload of a single completion as it would be sent to the
+// AI provider
+type Request struct {
+	Name            string          `json:"name"`
+	Provider        string          `json:"provider"`
+	Model           string          `json:"model"`
+	Temperature     float32         `json:"temperature"`
+	Prompt          string          `json:"prompt"`
+	Schema          json.RawMessage `json:"schema"`
+	EstimatedTokens int             `json:"estimatedPromptTokens"`
+}
+
+// Option configures optional Client behaviour
+type Option func(*Client)
+
+// WithCache serves repeated requests from the given response cache
+func WithCache(store *cache.Cache) Option {
+	return func(c *Client) {
+		c.cache = store
+	}
+}
+
+// WithUsage records token usage and cost of every request with the tracker
+func WithUsage(tracker *usage.Tracker) Option {
+	return func(c *Client) {
+		c.usage = tracker
+	}
+}
+
+// WithRedactor redacts secrets from every input before it is sent, calling
+// report with what was replaced
+func WithRedactor(redactor *redact.Redactor, report func([]redact.Finding)) Option {
+	return func(c *Client) {
+		c.redactor = redactor
+		c.onRedact = report
+	}
+}
+
+// WithDryRun passes each request to handler instead of sending it
+func WithDryRun(handler func(Request) error) Option {
+	return func(c *Client) {
+		c.dryRun = handler
+	}
+}
+
+// EstimateTokens approximates the number of tokens in s using the common
+// four characters per token rule of thumb
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
 }
 
 // NewClient creates a new AI client
-func NewClient(apiKey string) *Client {
-    return &Client{
-        client: openai.NewClient(apiKey),
-    }
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
+		client: openai.NewClient(apiKey),
+		model:  model,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// GenerateCommitMessage generates a commit message from diff
-func (c *Client) GenerateCommitMessage(diff string) (*models.CommitMessage, error) {
-    prompt := fmt.Sprintf(commitMessagePrompt, diff)
-
-    resp, err := c.client.CreateChatCompletion(
-        context.Background(),
-        openai.ChatCompletionRequest{
-            Model: model,
-            Messages: []openai.ChatCompletionMessage{
-                {
-                    Role:    openai.ChatMessageRoleUser,
-                    Content: prompt,
-                },
-            },
-            Temperature: 0.3,
-            ResponseFormat: &openai.ChatCompletionResponseFormat{
-                Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
-                JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
-                    Name: "CommitMessage",
-                    Schema: json.RawMessage(`{
-						"type": "object",
-						"properties": {
-							"message": {
-								"type": "string"
-							}
-						},
-						"required": ["message"]
-					}`),
-                },
-            },
-        },
-    )
-
-    if err != nil {
-        return nil, fmt.Errorf("failed to generate commit message: %w", err)
-    }
-
-    var commitMsg models.CommitMessage
-    if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &commitMsg); err != nil {
-        return nil, fmt.Errorf("failed to parse AI response: %w", err)
-    }
-
-    return &commitMsg, nil
-}
-
-// GenerateMRDetails generates MR title and description from diff
-func (c *Client) GenerateMRDetails(diff string) (*models.MrDetails, error) {
-    prompt := fmt.Sprintf(`Analyze the following git diff and generate a MR title, description, and file summaries.
-
-Diff:
-%s
-
-Return a JSON object with this structure:
-{
-  "title": "MR title here",
-  "description": "Detailed MR description here",
-  "fileSummaries": [
-    {
-      "file": "path/to/file",
-      "description": "One-sentence summary of changes"
-    }
-  ]
-}`, diff)
-
-    resp, err := c.client.CreateChatCompletion(
-        context.Background(),
-        openai.ChatCompletionRequest{
-            Model: model,
-            Messages: []openai.ChatCompletionMessage{
-                {
-                    Role:    openai.ChatMessageRoleUser,
-                    Content: prompt,
-                },
-            },
-            Temperature: 0.3,
-            ResponseFormat: &openai.ChatCompletionResponseFormat{
-                Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
-                JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
-                    Name: "PrDetails",
-                    Schema: json.RawMessage(`{
-						"type": "object",
-						"properties": {
-							"title": {
-								"type": "string"
-							},
-							"description": {
-								"type": "string"
-							},
-							"fileSummaries": {
-								"type": "array",
-								"items": {
-									"type": "object",
-									"properties": {
-										"file": {
-											"type": "string"
-										},
-										"description": {
-											"type": "string"
-										}
-									},
-									"required": ["file", "description"]
-								}
-							}
-						},
-						"required": ["title", "description", "fileSummaries"]
-					}`),
-                },
-            },
-        },
-    )
-
-    if err != nil {
-        return nil, fmt.Errorf("failed to generate PR details: %w", err)
-    }
-
-    var prDetails models.MrDetails
-    if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &prDetails); err != nil {
-        return nil, fmt.Errorf("failed to parse AI response: %w", err)
-    }
-
-    return &prDetails, nil
+// completion describes a single structured chat completion request
+type completion struct {
+	name     string // name of the JSON schema
+	template string // prompt template the input is rendered into
+	input    string // diff or other data substituted into the template
+	schema   string // JSON schema the response must follow
 }
 
-// GenerateMRTitle generates a concise PR title from a diff
-func (c *Client) GenerateMRTitle(diff string) (string, error) {
-	prompt := fmt.Sprintf(mrTitlePrompt, diff)
+// complete runs the completion and decodes the JSON response into out,
+// serving it from the cache when the same request was made before
+func (c *Client) complete(req completion, out any) error {
+	if c.redactor != nil {
+		var findings []redact.Finding
+		req.input, findings = c.redactor.Redact(req.input)
+		if len(findings) > 0 && c.onRedact != nil {
+			c.onRedact(findings)
+		}
+	}
+
+	prompt := fmt.Sprintf(req.template, req.input)
+	if c.dryRun != nil {
+		err := c.dryRun(Request{
+			Name:            req.name,
+			Provider:        provider,
+			Model:           c.model,
+			Temperature:     temperature,
+			Prompt:          prompt,
+			Schema:          json.RawMessage(req.schema),
+			EstimatedTokens: EstimateTokens(prompt) + EstimateTokens(req.schema),
+		})
+		if err != nil {
+			return err
+		}
+		return ErrDryRun
+	}
+
+	key := cache.Key(provider, c.model, cache.Hash(req.template+req.schema), cache.Hash(req.input))
+	if c.cache != nil {
+		if content, ok := c.cache.Get(key); ok {
+			if err := json.Unmarshal([]byte(content), out); err == nil {
+				if c.usage != nil {
+					c.usage.RecordCacheHit()
+				}
+				return nil
+			}
+		}
+	}
+
+	if c.usage != nil {
+		if err := c.usage.Allow(); err != nil {
+			return err
+		}
+	}
 
 	resp, err := c.client.CreateChatCompletion(
 		context.Background(),
 		openai.ChatCompletionRequest{
-			Model: model,
+			Model: c.model,
 			Messages: []openai.ChatCompletionMessage{
 				{
 					Role:    openai.ChatMessageRoleUser,
 					Content: prompt,
 				},
 			},
-			Temperature: 0.3,
+			Temperature: temperature,
 			ResponseFormat: &openai.ChatCompletionResponseFormat{
 				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
 				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
-					Name: "PrTitle",
-					Schema: json.RawMessage(`{
-						"type": "object",
-						"properties": {
-							"title": {
-								"type": "string"
-							}
-						},
-						"required": ["title"]
-					}`),
+					Name:   req.name,
+					Schema: json.RawMessage(req.schema),
 				},
 			},
 		},
 	)
+	if err != nil {
+		return err
+	}
+	if c.usage != nil {
+		// The request has been paid for even if the ledger cannot be written
+		_ = c.usage.Record(c.model, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+	if len(resp.Choices) == 0 {
+		return fmt.Errorf("AI response contained no choices")
+	}
+
+	content := resp.Choices[0].Message.Content
+	if err := json.Unmarshal([]byte(content), out); err != nil {
+		return fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	if c.cache != nil {
+		// A failed cache write only costs a repeated request later
+		_ = c.cache.Put(key, content)
+	}
+	return nil
+}
 
+// GenerateCommitMessage generates a commit message from diff
+func (c *Client) GenerateCommitMessage(diff string) (*models.CommitMessage, error) {
+	var commitMsg models.CommitMessage
+	err := c.complete(completion{
+		name:     "CommitMessage",
+		template: commitMessagePrompt,
+		input:    diff,
+		schema:   commitMessageSchema,
+	}, &commitMsg)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate PR title: %w", err)
+		return nil, fmt.Errorf("failed to generate commit message: %w", err)
+	}
+
+	return &commitMsg, nil
+}
+
+// GenerateMRDetails generates MR title and description from diff. Each file
+// is summarised on its own so unchanged files are served from the cache, and
+// the title and description are then derived from those summaries and a
+// truncated copy of the diff.
+func (c *Client) GenerateMRDetails(diff string) (*models.MrDetails, error) {
+	summaries, err := c.summarizeFiles(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	var overview strings.Builder
+	for _, summary := range summaries {
+		fmt.Fprintf(&overview, "- %s: %s\n", summary.File, summary.Description)
+	}
+	input := fmt.Sprintf("## File summaries\n\n%s\n## Diff\n\n%s",
+		overview.String(), git.TruncateDiff(diff, detailsDiffFileLines, detailsDiffLines))
+
+	var prDetails models.MrDetails
+	err = c.complete(completion{
+		name:     "PrDetails",
+		template: mrDetailsPrompt,
+		input:    input,
+		schema:   mrDescriptionSchema,
+	}, &prDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PR details: %w", err)
+	}
+	prDetails.FileSummaries = summaries
+
+	return &prDetails, nil
+}
+
+// summarizeFiles generates a one-sentence summary for every file in diff
+func (c *Client) summarizeFiles(diff string) ([]models.FileSummary, error) {
+	var summaries []models.FileSummary
+	dryRun := false
+	for _, file := range git.SplitDiff(diff) {
+		var summary models.FileSummary
+		err := c.complete(completion{
+			name:     "FileSummary",
+			template: fileSummaryPrompt,
+			input:    file.Text,
+			schema:   fileSummarySchema,
+		}, &summary)
+		if errors.Is(err, ErrDryRun) {
+			// Show every file's request; the final request depends on the responses
+			dryRun = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarise %s: %w", file.Path(), err)
+		}
+		summary.File = file.Path()
+		summaries = append(summaries, summary)
 	}
+	if dryRun {
+		return nil, ErrDryRun
+	}
+	return summaries, nil
+}
 
+// GenerateMRTitle generates a concise PR title from a diff
+func (c *Client) GenerateMRTitle(diff string) (string, error) {
 	var prTitle models.MrTitle
-	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &prTitle); err != nil {
-		return "", fmt.Errorf("failed to parse AI response: %w", err)
+	err := c.complete(completion{
+		name:     "PrTitle",
+		template: mrTitlePrompt,
+		input:    diff,
+		schema:   mrTitleSchema,
+	}, &prTitle)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate PR title: %w", err)
 	}
 
 	return prTitle.Title, nil
@@ -193,56 +298,204 @@ func (c *Client) GenerateMRTitle(diff string) (string, error) {
 
 // ReviewMR generates review comments for a MR diff
 func (c *Client) ReviewMR(diff string) (*models.MrReviewDetails, error) {
-    prompt := fmt.Sprintf(reviewPrompt, diff)
-
-    resp, err := c.client.CreateChatCompletion(
-        context.Background(),
-        openai.ChatCompletionRequest{
-            Model: model,
-            Messages: []openai.ChatCompletionMessage{
-                {
-                    Role:    openai.ChatMessageRoleUser,
-                    Content: prompt,
-                },
-            },
-            Temperature: 0.3,
-						ResponseFormat: &openai.ChatCompletionResponseFormat{
-							Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
-							JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
-								Name: "PrReviewDetails",
-								Schema: json.RawMessage(`{
-									"type": "object",
-									"properties": {
-										"review": {
-											"type": "array",
-											"items": {
-												"type": "object",
-												"properties": {
-													"file": {"type": "string"},
-													"line": {"type": "integer"},
-													"category": {"type": "string"},
-													"comment": {"type": "string"},
-													"codeSnippet": {"type": "string"}
-												},
-												"required": ["file", "line", "category", "comment"]
-											}
-										}
-									},
-									"required": ["review"]
-								}`),
-							},
-						},
-        },
-    )
-
-    if err != nil {
-        return nil, fmt.Errorf("failed to generate PR review: %w", err)
-    }
-
-    var reviewDetails models.MrReviewDetails
-    if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &reviewDetails); err != nil {
-        return nil, fmt.Errorf("failed to parse AI response: %w", err)
-    }
-
-    return &reviewDetails, nil
-}
\ No newline at end of file
+	var reviewDetails models.MrReviewDetails
+	err := c.complete(completion{
+		name:     "PrReviewDetails",
+		template: reviewPrompt,
+		input:    diff,
+		schema:   reviewSchema,
+	}, &reviewDetails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PR review: %w", err)
+	}
+	for i := range reviewDetails.Review {
+		reviewDetails.Review[i].Normalize()
+	}
+
+	return &reviewDetails, nil
+}
+
+// GenerateChangelog rewrites numbered commit messages into user-facing
+// changelog entries and summarises their breaking changes
+func (c *Client) GenerateChangelog(commits string) (*models.ChangelogNotes, error) {
+	var notes models.ChangelogNotes
+	err := c.complete(completion{
+		name:     "Changelog",
+		template: changelogPrompt,
+		input:    commits,
+		schema:   changelogSchema,
+	}, &notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate changelog: %w", err)
+	}
+
+	return &notes, nil
+}
+
+// GenerateReleaseNotes clusters the commits and merge requests of a release
+// into highlights, features, fixes and breaking changes
+func (c *Client) GenerateReleaseNotes(history string) (*models.ReleaseNotes, error) {
+	var notes models.ReleaseNotes
+	err := c.complete(completion{
+		name:     "ReleaseNotes",
+		template: releaseNotesPrompt,
+		input:    history,
+		schema:   releaseNotesSchema,
+	}, &notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate release notes: %w", err)
+	}
+
+	return &notes, nil
+}
+
+// DetectBreakingChanges asks whether numbered commits that do not declare
+// a breaking change break compatibility nonetheless
+func (c *Client) DetectBreakingChanges(commits string) (*models.BreakingChangeReview, error) {
+	var review models.BreakingChangeReview
+	err := c.complete(completion{
+		name:     "BreakingChanges",
+		template: breakingChangesPrompt,
+		input:    commits,
+		schema:   breakingChangesSchema,
+	}, &review)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect breaking changes: %w", err)
+	}
+
+	return &review, nil
+}
+
+// GenerateTagMessage generates an annotated tag message for a release
+func (c *Client) GenerateTagMessage(release string) (*models.TagMessage, error) {
+	var tagMsg models.TagMessage
+	err := c.complete(completion{
+		name:     "TagMessage",
+		template: tagMessagePrompt,
+		input:    release,
+		schema:   tagMessageSchema,
+	}, &tagMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tag message: %w", err)
+	}
+
+	return &tagMsg, nil
+}
+
+// PlanSplit groups numbered hunks into atomic commits with a message each
+func (c *Client) PlanSplit(hunks string) (*models.SplitPlan, error) {
+	var plan models.SplitPlan
+	err := c.complete(completion{
+		name:     "SplitPlan",
+		template: splitPlanPrompt,
+		input:    hunks,
+		schema:   splitPlanSchema,
+	}, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan commits: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// ChooseFixupTargets picks the commit each numbered hunk fixes up from the
+// candidates listed with it
+func (c *Client) ChooseFixupTargets(hunks string) (*models.FixupChoices, error) {
+	var choices models.FixupChoices
+	err := c.complete(completion{
+		name:     "FixupChoices",
+		template: fixupChoicesPrompt,
+		input:    hunks,
+		schema:   fixupChoicesSchema,
+	}, &choices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to choose fixup targets: %w", err)
+	}
+
+	return &choices, nil
+}
+
+// GenerateSquashMessage generates a single commit message for a branch from
+// its commit messages and combined diff
+func (c *Client) GenerateSquashMessage(input string) (*models.CommitMessage, error) {
+	var commitMsg models.CommitMessage
+	err := c.complete(completion{
+		name:     "SquashMessage",
+		template: squashMessagePrompt,
+		input:    input,
+		schema:   commitMessageSchema,
+	}, &commitMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate squash message: %w", err)
+	}
+
+	return &commitMsg, nil
+}
+
+// GenerateMergeMessage generates the message of a merge commit, including
+// notes on resolved conflicts
+func (c *Client) GenerateMergeMessage(input string) (*models.CommitMessage, error) {
+	var commitMsg models.CommitMessage
+	err := c.complete(completion{
+		name:     "MergeMessage",
+		template: mergeMessagePrompt,
+		input:    input,
+		schema:   commitMessageSchema,
+	}, &commitMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate merge message: %w", err)
+	}
+
+	return &commitMsg, nil
+}
+
+// GenerateBranchName proposes the parts of a branch name for a description
+// of the work or a diff
+func (c *Client) GenerateBranchName(input string) (*models.BranchName, error) {
+	var name models.BranchName
+	err := c.complete(completion{
+		name:     "BranchName",
+		template: branchNamePrompt,
+		input:    input,
+		schema:   branchNameSchema,
+	}, &name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate branch name: %w", err)
+	}
+
+	return &name, nil
+}
+
+// ExplainChanges explains commits from their messages and diff at the depth
+// named at the start of input
+func (c *Client) ExplainChanges(input string) (*models.Explanation, error) {
+	var explanation models.Explanation
+	err := c.complete(completion{
+		name:     "Explanation",
+		template: explanationPrompt,
+		input:    input,
+		schema:   explanationSchema,
+	}, &explanation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain changes: %w", err)
+	}
+
+	return &explanation, nil
+}
+
+// NarrateLineHistory explains why lines of code exist from the commits that
+// introduced and changed them
+func (c *Client) NarrateLineHistory(history string) (*models.LineHistory, error) {
+	var lineHistory models.LineHistory
+	err := c.complete(completion{
+		name:     "LineHistory",
+		template: lineHistoryPrompt,
+		input:    history,
+		schema:   lineHistorySchema,
+	}, &lineHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to narrate line history: %w", err)
+	}
+
+	return &lineHistory, nil
+}
@@ -81,7 +81,6 @@ The header is a single line: "<type>[optional scope]: <description>"
 Analyze the following git diff and generate the commit message in the specified JSON format:\n %s
 `
 
-
 const mrTitlePrompt = `
 You are an expert software engineer writing a commit message. Your task is to analyze the provided git diff and generate a concise, professional PR title.
 
@@ -125,10 +124,284 @@ Your feedback must be focused on the following areas:
 - **review**: A list of considerations and potential improvements. For each item, provide:
   - "file": The file path.
   - "line": The line number.
-  - "category": The category of feedback (e.g., 'Security', 'Bug', 'Optimization', 'Improvement').
+  - "category": The category of feedback: one of 'Security', 'Bug', 'Performance' or 'Improvement'.
+  - "severity": How serious the issue is: 'critical' (exploitable vulnerability, data loss or crash), 'major' (incorrect behaviour that must be fixed before merging), 'minor' (should be fixed but does not block merging) or 'info' (optional suggestion).
   - "comment": A detailed, constructive comment explaining the issue and suggesting a fix.
   - "codeSnippet": The relevant code snippet.
 
 ## [Begin Task]
 Analyze the following git diff and generate the review in the specified JSON format:\n%s
 `
+
+const fileSummaryPrompt = `
+You are an expert software engineer summarising changes for a merge request. Your task is to analyze the provided git diff of a single file and describe what changed.
+
+## Constraints
+- Write exactly one sentence in the imperative mood.
+- Describe the effect of the change, not the individual lines.
+- Do **not** use emojis.
+
+## Output Structure (JSON)
+- **description**: A one-sentence summary of the changes in the file.
+
+---
+
+## [Begin Task]
+Analyze the following git diff and generate the summary in the specified JSON format:\n%s
+`
+
+const mrDetailsPrompt = `
+You are an expert software engineer writing a merge request. Your task is to analyze the provided per-file summaries of a change, together with its diff, and generate a MR title and description. Large diffs are truncated; rely on the summaries for the parts left out.
+
+## Format Requirements
+- **title**: Follow the [Conventional Commits](https://www.conventionalcommits.org/en/v1.0.0/) specification: "type(scope): subject".
+- **description**: Explain what the merge request changes and why, grouping related files together. Use Markdown paragraphs or bullet points.
+
+## Constraints
+- The tone must be professional and direct.
+- Do **not** use emojis.
+- Do **not** list every file individually; the file summaries are shown separately.
+
+## Output Structure (JSON)
+- **title**: A string for the MR title.
+- **description**: A string for the MR description.
+
+---
+
+## [Begin Task]
+Analyze the following file summaries and diff and generate the MR title and description in the specified JSON format:\n%s
+`
+
+const changelogPrompt = `
+You are an expert technical writer maintaining a project's changelog. Your task is to rewrite the provided commit messages into changelog entries for the project's users.
+
+## Format Requirements
+- **entries**: One entry per commit, with the commit's number as **id**. Each **text** is a single sentence in the past or present tense describing the change from the user's point of view, e.g. "Support for exporting reports as CSV" or "Crash when the config file is empty".
+- **breakingChanges**: One sentence per breaking change explaining what users must change when upgrading. Only commits marked with "!" or a "BREAKING CHANGE" footer are breaking. Use an empty list if there are none.
+
+## Constraints
+- Do **not** mention commit types, scopes, hashes or internal refactoring details.
+- Keep each entry short; do not end it with a period.
+- Do **not** use emojis.
+
+## Output Structure (JSON)
+- **entries**: An array of objects with **id** and **text**.
+- **breakingChanges**: An array of strings.
+
+---
+
+## [Begin Task]
+Rewrite the following commits into changelog entries in the specified JSON format:\n%s
+`
+
+const releaseNotesPrompt = `
+You are an expert technical writer preparing the release notes of a software project. Your task is to analyze the provided merged merge requests and commits of a release and cluster them into notes for the release page.
+
+## Format Requirements
+- **highlights**: The two to four most important changes for users, each one or two sentences explaining the benefit.
+- **features**: New functionality and improvements, one bullet per user-visible change.
+- **fixes**: Bug fixes, one bullet per fixed problem, describing the problem rather than the code change.
+- **breakingChanges**: Changes users must act on when upgrading, including what to change. Commits marked "[BREAKING]" are always breaking.
+
+## Constraints
+- Merge related commits and merge requests into a single bullet.
+- Leave out internal changes such as refactoring, tests, CI and dependency bumps unless they affect users.
+- Reference merge requests (e.g. "!12" or "#12") at the end of a bullet when one is available.
+- Use an empty list for any category without changes.
+- Do **not** use emojis.
+
+## Output Structure (JSON)
+- **highlights**, **features**, **fixes**, **breakingChanges**: Arrays of strings written in Markdown.
+
+---
+
+## [Begin Task]
+Analyze the following release history and generate the release notes in the specified JSON format:\n%s
+`
+
+const breakingChangesPrompt = `
+You are an expert software engineer responsible for semantic versioning. Your task is to decide for each of the provided commits whether it introduces a breaking change although its message does not declare one.
+
+## Definition
+A change is breaking if existing users or dependent code must change to keep working after upgrading, for example:
+- Removing or renaming a public function, type, command, flag, option, endpoint or configuration key.
+- Changing the signature, return values, defaults or documented behaviour of a public interface.
+- Changing a file, wire or storage format in an incompatible way.
+
+Internal refactoring, new optional functionality and bug fixes restoring documented behaviour are **not** breaking.
+
+## Output Structure (JSON)
+- **commits**: One object per commit with its number as **id**, **breaking** set to true or false, and a one-sentence **reason**.
+
+---
+
+## [Begin Task]
+Analyze the following commits and generate the verdicts in the specified JSON format:\n%s
+`
+
+const tagMessagePrompt = `
+You are an expert software engineer tagging a release. Your task is to write the message of an annotated git tag from the release version and the commits it contains.
+
+## Format Requirements
+- The first line is "Release <version>".
+- Follow it with a blank line and a short bullet list of the most notable user-facing changes, breaking changes first.
+- Wrap lines at 72 characters.
+
+## Constraints
+- Do **not** list internal changes such as refactoring, tests or CI.
+- Do **not** use Markdown headings or emojis.
+
+## Output Structure (JSON)
+- **message**: A string containing the complete tag message.
+
+---
+
+## [Begin Task]
+Analyze the following release and generate the tag message in the specified JSON format:\n%s
+`
+
+const splitPlanPrompt = `
+You are an expert senior software engineer preparing a clean Git history. Your task is to split the provided staged changes, given as numbered hunks, into a sequence of small, atomic commits.
+
+## Guiding Principles
+1.  **One Logical Change per Commit:** Each commit must contain one coherent change, e.g. a refactoring, a bug fix or a feature, so it can be reviewed and reverted on its own.
+2.  **Keep Dependent Changes Together:** Hunks that only work together (e.g. a new function and its first caller, or a renamed symbol and all its uses) belong in the same commit.
+3.  **Order Matters:** Commits are created in the order you list them. Each commit should build on the previous ones only.
+4.  **Do Not Over-Split:** If the changes form a single logical unit, return a single commit.
+
+## Format Requirements
+- **message**: A complete commit message following the [Conventional Commits](https://www.conventionalcommits.org/en/v1.0.0/) specification: "type(scope): description", optionally followed by a blank line and a body explaining why.
+- **hunks**: The numbers of the hunks in the commit. Every hunk must appear in exactly one commit.
+
+## Output Structure (JSON)
+- **commits**: An array of objects with **message** and **hunks**, in the order they should be committed.
+
+---
+
+## [Begin Task]
+Analyze the following hunks and generate the commit plan in the specified JSON format:\n%s
+`
+
+const fixupChoicesPrompt = `
+You are an expert software engineer cleaning up a feature branch before review. Each of the provided hunks is a small correction that belongs to one of the earlier commits on the branch, which will be squashed with it. Git blame found several candidate commits for each hunk.
+
+## Task
+For each hunk, choose the candidate commit whose change the hunk corrects or completes, judging from the hunk's content and the commit messages.
+
+## Constraints
+- Only answer with one of the hunk's candidate commit hashes, exactly as given.
+- If none of the candidates fits, answer with an empty string.
+
+## Output Structure (JSON)
+- **hunks**: An array of objects with the hunk number as **id** and the chosen **commit** hash.
+
+---
+
+## [Begin Task]
+Analyze the following hunks and choose their commits in the specified JSON format:\n%s
+`
+
+const squashMessagePrompt = `
+You are an expert senior software engineer squashing a feature branch into a single commit. Your task is to write one commit message for the whole branch from the messages of its individual commits and the combined diff.
+
+## Guiding Principles
+1.  **Describe the Result, Not the Journey:** The individual messages are often work-in-progress notes like "wip" or "fix typo". Use them as hints about intent, but describe the final change the diff shows, not the steps taken to get there.
+2.  **Explain the "Why":** Use the body to explain why the change was made and summarize its main parts. Leave out changes that were made and undone on the branch.
+3.  **Keep Important Footers:** Keep breaking change notes and issue references (e.g. "Closes: #123") from the individual messages if they still apply.
+
+## Format Requirements
+- Follow the [Conventional Commits](https://www.conventionalcommits.org/en/v1.0.0/) specification: "type(scope): description", a blank line, an optional body and optional footers.
+- **type**: One of "feat", "fix", "improvement", "refactor", "perf", "docs", "style", "test", "build", "ci", "ops", "chore", "revert", "security" or "deprecate", chosen for the most significant change.
+- The description uses the imperative mood, starts with a lowercase letter and does not end with a period.
+- Wrap body lines at 72 characters. Do not use emojis.
+
+## Output Structure (JSON)
+- **message**: The complete commit message.
+
+---
+
+## [Begin Task]
+Analyze the following commits and diff and generate the commit message in the specified JSON format:\n%s
+`
+
+const mergeMessagePrompt = `
+You are an expert senior software engineer writing the message of a merge commit. Your task is to describe what the merge brings in, based on the default merge message, the merged commits and their diff.
+
+## Format Requirements
+- Keep the first line of the default merge message (e.g. "Merge branch 'feature' into main") unchanged as the header.
+- After a blank line, summarize in a few sentences or bullet points what the merged changes do and why.
+- If conflicts were resolved, add a "Conflicts resolved:" paragraph listing each conflicted file with a short note on how the conflict was resolved, judging from the staged result compared to both sides.
+- Wrap body lines at 72 characters. Do not use emojis.
+
+## Output Structure (JSON)
+- **message**: The complete commit message.
+
+---
+
+## [Begin Task]
+Analyze the following merge and generate the commit message in the specified JSON format:\n%s
+`
+
+const branchNamePrompt = `
+You are an expert software engineer naming a Git branch. Your task is to derive the parts of a short, descriptive branch name from either a description of the planned work or the diff of the changes made so far.
+
+## Format Requirements
+- **type**: The kind of work, one of "feat", "fix", "improvement", "refactor", "perf", "docs", "style", "test", "build", "ci", "ops", "chore", "revert", "security" or "deprecate".
+- **ticket**: The issue or ticket reference mentioned in the input, e.g. "PROJ-123" or "123" for "#123", exactly as written without the "#". Use an empty string if none is mentioned. Never invent one.
+- **slug**: Two to five lowercase English words separated by hyphens that summarize the work, e.g. "retry-failed-uploads". Do not repeat the type or the ticket.
+
+## Output Structure (JSON)
+- An object with **type**, **ticket** and **slug**.
+
+---
+
+## [Begin Task]
+Analyze the following input and generate the branch name parts in the specified JSON format:\n%s
+`
+
+const explanationPrompt = `
+You are an expert senior software engineer explaining changes to a colleague who is new to the codebase. Your task is to explain what the provided commit or range of commits does and why, based on the commit messages and the diff.
+
+## Depth
+The input starts with the requested depth:
+- **brief**: Keep every part short. The intent is a single paragraph, each component change is one short sentence and the walkthrough is empty.
+- **detailed**: Explain the intent thoroughly and walk through the changes step by step in the order a reviewer should read them.
+
+## Format Requirements
+- **intent**: What the changes achieve and why they were made, in plain language. Do not just repeat the commit messages.
+- **components**: The affected parts of the codebase (packages, modules, features) with **name** and what **change** they undergo.
+- **risk**: A **level** of "low", "medium" or "high" and the **reason**, e.g. what could break, who is affected and how well the change is covered by tests.
+- **followUps**: Work the changes leave open or suggest, e.g. missing tests, documentation or cleanups. Use an empty array if there is none.
+- **walkthrough**: For detailed explanations, the steps of the walkthrough, each a short paragraph referring to files and functions. Empty for brief explanations.
+
+## Constraints
+- Base the explanation only on the input. Say so when the intent is unclear instead of guessing.
+- Do not use emojis.
+
+---
+
+## [Begin Task]
+Analyze the following changes and generate the explanation in the specified JSON format:\n%s
+`
+
+const lineHistoryPrompt = `
+You are an expert software engineer doing code archaeology. Your task is to explain why the provided lines of code look the way they do, based on the commits that introduced and changed them.
+
+## Input
+- The lines as they are now, each with the commit that last changed it.
+- The commits that changed the lines, newest first, each with its message and the changes it made to the lines. The oldest commit introduced them. Some intermediate commits may be left out.
+
+## Format Requirements
+- **narrative**: The history of the lines in a few sentences, oldest first: how they were introduced and how and why they changed since.
+- **rationale**: Why the code is here and why it has its current form, as far as the commit messages and changes reveal it. Say so when the reason is not recorded instead of guessing.
+- **timeline**: One entry per commit, oldest first, with the abbreviated **commit** hash as given and a one-sentence **summary** of what it did to the lines.
+
+## Constraints
+- Base the answer only on the input.
+- Do not use emojis.
+
+---
+
+## [Begin Task]
+Analyze the following history and generate the explanation in the specified JSON format:\n%s
+`
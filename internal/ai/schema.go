@@ -0,0 +1,229 @@
+package ai
+
+const commitMessageSchema = `{
+	"type": "object",
+	"properties": {
+		"message": {
+			"type": "string"
+		}
+	},
+	"required": ["message"]
+}`
+
+const fileSummarySchema = `{
+	"type": "object",
+	"properties": {
+		"description": {
+			"type": "string"
+		}
+	},
+	"required": ["description"]
+}`
+
+const mrDescriptionSchema = `{
+	"type": "object",
+	"properties": {
+		"title": {
+			"type": "string"
+		},
+		"description": {
+			"type": "string"
+		}
+	},
+	"required": ["title", "description"]
+}`
+
+const mrTitleSchema = `{
+	"type": "object",
+	"properties": {
+		"title": {
+			"type": "string"
+		}
+	},
+	"required": ["title"]
+}`
+
+const reviewSchema = `{
+	"type": "object",
+	"properties": {
+		"review": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"file": {"type": "string"},
+					"line": {"type": "integer"},
+					"category": {"type": "string", "enum": ["Security", "Bug", "Performance", "Improvement"]},
+					"severity": {"type": "string", "enum": ["info", "minor", "major", "critical"]},
+					"comment": {"type": "string"},
+					"codeSnippet": {"type": "string"}
+				},
+				"required": ["file", "line", "category", "severity", "comment"]
+			}
+		}
+	},
+	"required": ["review"]
+}`
+
+const changelogSchema = `{
+	"type": "object",
+	"properties": {
+		"entries": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"text": {"type": "string"}
+				},
+				"required": ["id", "text"]
+			}
+		},
+		"breakingChanges": {
+			"type": "array",
+			"items": {"type": "string"}
+		}
+	},
+	"required": ["entries", "breakingChanges"]
+}`
+
+const releaseNotesSchema = `{
+	"type": "object",
+	"properties": {
+		"highlights": {"type": "array", "items": {"type": "string"}},
+		"features": {"type": "array", "items": {"type": "string"}},
+		"fixes": {"type": "array", "items": {"type": "string"}},
+		"breakingChanges": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["highlights", "features", "fixes", "breakingChanges"]
+}`
+
+const breakingChangesSchema = `{
+	"type": "object",
+	"properties": {
+		"commits": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"breaking": {"type": "boolean"},
+					"reason": {"type": "string"}
+				},
+				"required": ["id", "breaking", "reason"]
+			}
+		}
+	},
+	"required": ["commits"]
+}`
+
+const tagMessageSchema = `{
+	"type": "object",
+	"properties": {
+		"message": {
+			"type": "string"
+		}
+	},
+	"required": ["message"]
+}`
+
+const splitPlanSchema = `{
+	"type": "object",
+	"properties": {
+		"commits": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"message": {"type": "string"},
+					"hunks": {"type": "array", "items": {"type": "integer"}}
+				},
+				"required": ["message", "hunks"]
+			}
+		}
+	},
+	"required": ["commits"]
+}`
+
+const fixupChoicesSchema = `{
+	"type": "object",
+	"properties": {
+		"hunks": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "integer"},
+					"commit": {"type": "string"}
+				},
+				"required": ["id", "commit"]
+			}
+		}
+	},
+	"required": ["hunks"]
+}`
+
+const branchNameSchema = `{
+	"type": "object",
+	"properties": {
+		"type": {"type": "string"},
+		"ticket": {"type": "string"},
+		"slug": {"type": "string"}
+	},
+	"required": ["type", "ticket", "slug"]
+}`
+
+const explanationSchema = `{
+	"type": "object",
+	"properties": {
+		"intent": {"type": "string"},
+		"components": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"change": {"type": "string"}
+				},
+				"required": ["name", "change"]
+			}
+		},
+		"risk": {
+			"type": "object",
+			"properties": {
+				"level": {"type": "string", "enum": ["low", "medium", "high"]},
+				"reason": {"type": "string"}
+			},
+			"required": ["level", "reason"]
+		},
+		"followUps": {
+			"type": "array",
+			"items": {"type": "string"}
+		},
+		"walkthrough": {
+			"type": "array",
+			"items": {"type": "string"}
+		}
+	},
+	"required": ["intent", "components", "risk", "followUps", "walkthrough"]
+}`
+
+const lineHistorySchema = `{
+	"type": "object",
+	"properties": {
+		"narrative": {"type": "string"},
+		"rationale": {"type": "string"},
+		"timeline": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"commit": {"type": "string"},
+					"summary": {"type": "string"}
+				},
+				"required": ["commit", "summary"]
+			}
+		}
+	},
+	"required": ["narrative", "rationale", "timeline"]
+}`
@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const entryExt = ".json"
+
+// tmpPrefix starts the names of entries being written. A process that dies
+// before renaming one leaves it behind; prune removes those older than
+// staleTmpAge, which no running process can still be writing.
+const (
+	tmpPrefix   = "tmp-"
+	staleTmpAge = time.Hour
+)
+
+// Cache stores AI responses on disk so identical requests are only paid once
+type Cache struct {
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+}
+
+// Stats describes the current contents of the cache
+type Stats struct {
+	Dir     string    `json:"dir" yaml:"dir"`
+	Entries int       `json:"entries" yaml:"entries"`
+	Expired int       `json:"expired" yaml:"expired"`
+	Size    int64     `json:"sizeBytes" yaml:"sizeBytes"`
+	Oldest  time.Time `json:"oldest" yaml:"oldest"`
+	Newest  time.Time `json:"newest" yaml:"newest"`
+}
+
+// New creates a cache rooted at dir. A zero ttl keeps entries forever and a
+// zero maxSize disables size-based eviction.
+func New(dir string, ttl time.Duration, maxSize int64) *Cache {
+	return &Cache{
+		dir:     dir,
+		ttl:     ttl,
+		maxSize: maxSize,
+	}
+}
+
+// DefaultDir returns the response cache directory inside the user cache dir
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, "gitai", "responses"), nil
+}
+
+// Hash returns the hex encoded SHA-256 of s
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Key builds a cache key from the given parts
+func Key(parts ...string) string {
+	return Hash(strings.Join(parts, "\x00"))
+}
+
+// Get returns the cached value for key, if present and not expired
+func (c *Cache) Get(key string) (string, bool) {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	if c.expired(info, time.Now()) {
+		os.Remove(path)
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Put stores value under key and evicts old entries if the cache is too large
+func (c *Cache) Put(key, value string) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, tmpPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.WriteString(value); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return c.prune()
+}
+
+// Stats reports the number, size and age of cached entries
+func (c *Cache) Stats() (Stats, error) {
+	stats := Stats{Dir: c.dir}
+	entries, err := c.entries()
+	if err != nil {
+		return stats, err
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		stats.Entries++
+		stats.Size += entry.Size()
+		if c.expired(entry, now) {
+			stats.Expired++
+		}
+		if stats.Oldest.IsZero() || entry.ModTime().Before(stats.Oldest) {
+			stats.Oldest = entry.ModTime()
+		}
+		if entry.ModTime().After(stats.Newest) {
+			stats.Newest = entry.ModTime()
+		}
+	}
+	return stats, nil
+}
+
+// Clear removes every cached entry
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// prune drops stale temporary files, expired entries and then the oldest
+// entries until the cache fits within maxSize
+func (c *Cache) prune() error {
+	c.removeStaleTmp(time.Now())
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().Before(entries[j].ModTime())
+	})
+
+	now := time.Now()
+	var total int64
+	live := entries[:0]
+	for _, entry := range entries {
+		if c.expired(entry, now) {
+			os.Remove(filepath.Join(c.dir, entry.Name()))
+			continue
+		}
+		total += entry.Size()
+		live = append(live, entry)
+	}
+
+	for _, entry := range live {
+		if c.maxSize <= 0 || total <= c.maxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err == nil {
+			total -= entry.Size()
+		}
+	}
+	return nil
+}
+
+func (c *Cache) entries() ([]fs.FileInfo, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []fs.FileInfo
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), entryExt) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, info)
+	}
+	return entries, nil
+}
+
+// removeStaleTmp deletes temporary files left behind by interrupted writes
+func (c *Cache) removeStaleTmp(now time.Time) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasPrefix(dirEntry.Name(), tmpPrefix) {
+			continue
+		}
+		if info, err := dirEntry.Info(); err == nil && now.Sub(info.ModTime()) > staleTmpAge {
+			os.Remove(filepath.Join(c.dir, dirEntry.Name()))
+		}
+	}
+}
+
+func (c *Cache) expired(info fs.FileInfo, now time.Time) bool {
+	return c.ttl > 0 && now.Sub(info.ModTime()) > c.ttl
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+entryExt)
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// age moves the modification time of key's entry into the past
+func age(t *testing.T, c *Cache, key string, by time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-by)
+	if err := os.Chtimes(c.path(key), old, old); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetPut(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "responses"), 0, 0)
+
+	if _, ok := c.Get(Key("missing")); ok {
+		t.Error("Get() of a missing key succeeded")
+	}
+	key := Key("model", "prompt")
+	if err := c.Put(key, `{"message":"feat: add cache"}`); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.Get(key); !ok || got != `{"message":"feat: add cache"}` {
+		t.Errorf("Get() = %q, %v", got, ok)
+	}
+	if err := c.Put(key, "replaced"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.Get(key); got != "replaced" {
+		t.Errorf("Get() after overwrite = %q", got)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("Key() does not separate its parts")
+	}
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("Key() is not deterministic")
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	tests := map[string]struct {
+		ttl   time.Duration
+		age   time.Duration
+		found bool
+	}{
+		"fresh":        {ttl: time.Hour, age: time.Minute, found: true},
+		"expired":      {ttl: time.Hour, age: 2 * time.Hour, found: false},
+		"no ttl keeps": {ttl: 0, age: 24 * 365 * time.Hour, found: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := New(t.TempDir(), test.ttl, 0)
+			if err := c.Put("key", "value"); err != nil {
+				t.Fatal(err)
+			}
+			age(t, c, "key", test.age)
+
+			if _, ok := c.Get("key"); ok != test.found {
+				t.Errorf("Get() found = %v, want %v", ok, test.found)
+			}
+			if _, err := os.Stat(c.path("key")); !test.found && err == nil {
+				t.Error("expired entry was not removed")
+			}
+		})
+	}
+}
+
+func TestPruneBySize(t *testing.T) {
+	dir := t.TempDir()
+	unlimited := New(dir, 0, 0)
+	for i, key := range []string{"oldest", "middle"} {
+		if err := unlimited.Put(key, strings.Repeat("x", 10)); err != nil {
+			t.Fatal(err)
+		}
+		age(t, unlimited, key, time.Duration(2-i)*time.Minute)
+	}
+
+	c := New(dir, 0, 25)
+	if err := c.Put("newest", strings.Repeat("x", 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Get("oldest"); ok {
+		t.Error("oldest entry was kept although the cache is over its size")
+	}
+	for _, key := range []string{"middle", "newest"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("entry %s was pruned", key)
+		}
+	}
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 || stats.Size != 20 {
+		t.Errorf("Stats() = %+v, want 2 entries of 20 bytes", stats)
+	}
+}
+
+func TestPruneRemovesStaleTmpFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir, 0, 0)
+	stale, fresh := filepath.Join(dir, tmpPrefix+"stale"), filepath.Join(dir, tmpPrefix+"fresh")
+	for _, name := range []string{stale, fresh} {
+		if err := os.WriteFile(name, []byte("partial"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleTmpAge)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Put("key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); err == nil {
+		t.Error("stale temporary file was kept")
+	}
+	// A fresh one may still be written by another process
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("fresh temporary file was removed: %v", err)
+	}
+}
+
+func TestClear(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "responses"), 0, 0)
+	if err := c.Put("key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("key"); ok {
+		t.Error("Get() after Clear() succeeded")
+	}
+	stats, err := c.Stats()
+	if err != nil || stats.Entries != 0 {
+		t.Errorf("Stats() after Clear() = %+v, %v", stats, err)
+	}
+	// Clearing a missing cache is not an error
+	if err := c.Clear(); err != nil {
+		t.Errorf("second Clear() = %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/richardamare/gitai/internal/conventional"
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// Unreleased is the version of changes that are not tagged yet
+const Unreleased = "Unreleased"
+
+// Keep a Changelog section titles, in the order they are written
+const (
+	Added      = "Added"
+	Changed    = "Changed"
+	Deprecated = "Deprecated"
+	Removed    = "Removed"
+	Fixed      = "Fixed"
+	Security   = "Security"
+)
+
+var sectionOrder = []string{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// sectionTypes maps Conventional Commit types to the section they are
+// listed in. Other types (docs, chore, ci, ...) are left out unless breaking.
+var sectionTypes = map[string]string{
+	"feat":        Added,
+	"improvement": Changed,
+	"perf":        Changed,
+	"refactor":    Changed,
+	"revert":      Changed,
+	"deprecate":   Deprecated,
+	"fix":         Fixed,
+	"security":    Security,
+}
+
+// Release is the changelog section of one version
+type Release struct {
+	Version         string    `json:"version" yaml:"version"`
+	Date            string    `json:"date,omitempty" yaml:"date,omitempty"`
+	BreakingChanges []string  `json:"breakingChanges,omitempty" yaml:"breakingChanges,omitempty"`
+	Sections        []Section `json:"sections" yaml:"sections"`
+	// Unconventional lists the commits left out because they do not follow
+	// the Conventional Commits specification, as "<hash> <subject>"
+	Unconventional []string `json:"unconventional,omitempty" yaml:"unconventional,omitempty"`
+}
+
+// Section groups the entries of one kind of change
+type Section struct {
+	Title   string  `json:"title" yaml:"title"`
+	Entries []Entry `json:"entries" yaml:"entries"`
+}
+
+// Entry is a single changelog line
+type Entry struct {
+	Commit   string `json:"commit" yaml:"commit"`
+	Type     string `json:"type,omitempty" yaml:"type,omitempty"`
+	Scope    string `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Text     string `json:"text" yaml:"text"`
+	Breaking bool   `json:"breaking,omitempty" yaml:"breaking,omitempty"`
+
+	// message is the original commit message the entry was built from
+	message string
+}
+
+// Build groups commits by Conventional Commit type. Commits that do not
+// follow the specification, such as "wip", are left out and reported in
+// Unconventional.
+func Build(version, date string, commits []git.Commit) *Release {
+	release := &Release{Version: version, Date: date}
+	entries := map[string][]Entry{}
+
+	// git log lists the newest commit first; changelogs read oldest first
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		parsed := conventional.Parse(commit.Message())
+
+		if !parsed.Conventional() {
+			release.Unconventional = append(release.Unconventional, commit.ShortHash()+" "+commit.Subject)
+			continue
+		}
+		title, ok := sectionTypes[parsed.Type]
+		if !ok && parsed.Breaking {
+			title, ok = Changed, true
+		}
+		if !ok {
+			continue
+		}
+
+		entries[title] = append(entries[title], Entry{
+			Commit:   commit.ShortHash(),
+			Type:     parsed.Type,
+			Scope:    parsed.Scope,
+			Text:     capitalize(parsed.Description),
+			Breaking: parsed.Breaking,
+			message:  commit.Message(),
+		})
+		if parsed.Breaking {
+			release.BreakingChanges = append(release.BreakingChanges, capitalize(cmp.Or(parsed.BreakingNote, parsed.Description)))
+		}
+	}
+
+	for _, title := range sectionOrder {
+		if len(entries[title]) > 0 {
+			release.Sections = append(release.Sections, Section{Title: title, Entries: entries[title]})
+		}
+	}
+	return release
+}
+
+// Empty reports whether the release has no entries
+func (r *Release) Empty() bool {
+	return len(r.Sections) == 0
+}
+
+// PromptInput lists the entries' commit messages numbered in order, as the
+// input for the AI to rewrite
+func (r *Release) PromptInput() string {
+	var b strings.Builder
+	id := 1
+	for _, section := range r.Sections {
+		for _, entry := range section.Entries {
+			fmt.Fprintf(&b, "### Commit %d (%s)\n%s\n\n", id, section.Title, entry.message)
+			id++
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Apply replaces entry texts and the breaking change summary with the
+// AI-written notes. Entries the notes do not mention keep their text.
+func (r *Release) Apply(notes *models.ChangelogNotes) {
+	texts := map[int]string{}
+	for _, entry := range notes.Entries {
+		if text := strings.TrimSpace(entry.Text); text != "" {
+			texts[entry.ID] = text
+		}
+	}
+
+	id := 1
+	for i := range r.Sections {
+		for j := range r.Sections[i].Entries {
+			if text, ok := texts[id]; ok {
+				r.Sections[i].Entries[j].Text = text
+			}
+			id++
+		}
+	}
+
+	if len(notes.BreakingChanges) > 0 {
+		r.BreakingChanges = notes.BreakingChanges
+	}
+}
+
+// Heading returns the Markdown heading of the release
+func (r *Release) Heading() string {
+	if r.Date == "" {
+		return fmt.Sprintf("## [%s]", r.Version)
+	}
+	return fmt.Sprintf("## [%s] - %s", r.Version, r.Date)
+}
+
+// RenderMarkdown writes the release as a Keep a Changelog section
+func (r *Release) RenderMarkdown(w io.Writer) error {
+	fmt.Fprintln(w, r.Heading())
+	if len(r.BreakingChanges) > 0 {
+		fmt.Fprint(w, "\n### Breaking Changes\n\n")
+		for _, change := range r.BreakingChanges {
+			fmt.Fprintf(w, "- %s\n", change)
+		}
+	}
+	for _, section := range r.Sections {
+		fmt.Fprintf(w, "\n### %s\n\n", section.Title)
+		for _, entry := range section.Entries {
+			if entry.Scope != "" {
+				fmt.Fprintf(w, "- **%s:** %s\n", entry.Scope, entry.Text)
+			} else {
+				fmt.Fprintf(w, "- %s\n", entry.Text)
+			}
+		}
+	}
+	return nil
+}
+
+// RenderText writes the release as Markdown, which reads well in a terminal
+func (r *Release) RenderText(w io.Writer) error {
+	return r.RenderMarkdown(w)
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,212 @@
+package changelog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// commits returns git log output for messages given oldest first
+func commits(messages ...string) []git.Commit {
+	var result []git.Commit
+	for i := len(messages) - 1; i >= 0; i-- {
+		subject, body, _ := strings.Cut(messages[i], "\n\n")
+		result = append(result, git.Commit{Hash: strings.Repeat(string(rune('a'+i)), 40), Subject: subject, Body: body})
+	}
+	return result
+}
+
+func TestBuild(t *testing.T) {
+	release := Build("1.2.0", "2024-05-01", commits(
+		"feat(api): add pagination",
+		"fix: handle empty diffs",
+		"chore: update dependencies",
+		"docs: fix typo",
+		"Update README",
+		"perf: cache parsed configs",
+		"chore!: drop Go 1.20\n\nBREAKING CHANGE: Go 1.21 or later is required",
+		"feat!: remove the v1 endpoints",
+		"security: escape HTML in comments",
+	))
+
+	var titles []string
+	for _, section := range release.Sections {
+		var texts []string
+		for _, entry := range section.Entries {
+			texts = append(texts, entry.Text)
+		}
+		titles = append(titles, section.Title+": "+strings.Join(texts, "; "))
+	}
+	want := []string{
+		"Added: Add pagination; Remove the v1 endpoints",
+		"Changed: Cache parsed configs; Drop Go 1.20",
+		"Fixed: Handle empty diffs",
+		"Security: Escape HTML in comments",
+	}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("sections =\n%s\nwant\n%s", strings.Join(titles, "\n"), strings.Join(want, "\n"))
+	}
+
+	wantBreaking := []string{"Go 1.21 or later is required", "Remove the v1 endpoints"}
+	if !reflect.DeepEqual(release.BreakingChanges, wantBreaking) {
+		t.Errorf("BreakingChanges = %q, want %q", release.BreakingChanges, wantBreaking)
+	}
+
+	if !reflect.DeepEqual(release.Unconventional, []string{"eeeeeee Update README"}) {
+		t.Errorf("Unconventional = %q, want the commit without a type", release.Unconventional)
+	}
+
+	entry := release.Sections[0].Entries[0]
+	if entry.Commit != "aaaaaaa" || entry.Scope != "api" || entry.Type != "feat" {
+		t.Errorf("first entry = %+v", entry)
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	if release := Build(Unreleased, "", commits("chore: release", "ci: cache modules")); !release.Empty() {
+		t.Errorf("Build() of chores = %+v, want an empty release", release)
+	}
+	release := Build(Unreleased, "", commits("wip", "fixup! feat: add pagination"))
+	if !release.Empty() || len(release.Unconventional) != 2 {
+		t.Errorf("Build() of unconventional commits = %+v, want them only reported", release)
+	}
+}
+
+func TestApply(t *testing.T) {
+	release := Build("1.0.0", "", commits("feat: a", "fix: b", "fix: c"))
+	release.Apply(&models.ChangelogNotes{
+		Entries:         []models.ChangelogEntry{{ID: 1, Text: "Rewritten a"}, {ID: 3, Text: "  "}},
+		BreakingChanges: []string{"Summary"},
+	})
+
+	if got := release.Sections[0].Entries[0].Text; got != "Rewritten a" {
+		t.Errorf("entry 1 = %q", got)
+	}
+	if got := release.Sections[1].Entries[1].Text; got != "C" {
+		t.Errorf("entry 3 = %q, want the original text for an empty note", got)
+	}
+	if !reflect.DeepEqual(release.BreakingChanges, []string{"Summary"}) {
+		t.Errorf("BreakingChanges = %q", release.BreakingChanges)
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	release := Build("1.2.0", "2024-05-01", commits("feat(api)!: remove v1", "fix: handle nil"))
+	var b strings.Builder
+	release.RenderMarkdown(&b)
+
+	want := `## [1.2.0] - 2024-05-01
+
+### Breaking Changes
+
+- Remove v1
+
+### Added
+
+- **api:** Remove v1
+
+### Fixed
+
+- Handle nil
+`
+	if b.String() != want {
+		t.Errorf("RenderMarkdown() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	release := &Release{Version: "1.1.0", Date: "2024-05-01", Sections: []Section{{Title: Fixed, Entries: []Entry{{Text: "New fix"}}}}}
+	unreleased := &Release{Version: Unreleased, Sections: []Section{{Title: Added, Entries: []Entry{{Text: "Pending"}}}}}
+	const section = "## [1.1.0] - 2024-05-01\n\n### Fixed\n\n- New fix\n"
+
+	tests := []struct {
+		name      string
+		changelog string
+		release   *Release
+		want      string
+	}{
+		{
+			name:    "new file",
+			release: release,
+			want:    header + section,
+		},
+		{
+			name:      "above the previous release",
+			changelog: "# Changelog\n\n## [1.0.0] - 2024-01-01\n\n### Added\n\n- First\n",
+			release:   release,
+			want:      "# Changelog\n\n" + section + "\n## [1.0.0] - 2024-01-01\n\n### Added\n\n- First\n",
+		},
+		{
+			name:      "replaces the Unreleased section",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Pending\n\n## [1.0.0]\n\n- First\n",
+			release:   release,
+			want:      "# Changelog\n\n" + section + "\n## [1.0.0]\n\n- First\n",
+		},
+		{
+			name:      "replaces the only Unreleased section",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n- Pending\n\n[Unreleased]: https://example.com/compare/v1.0.0...HEAD\n",
+			release:   release,
+			want:      "# Changelog\n\n" + section + "\n[Unreleased]: https://example.com/compare/v1.0.0...HEAD\n",
+		},
+		{
+			name:      "keeps Unreleased when the version has a section",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n- Pending\n\n## [1.1.0]\n\n- Old fix\n",
+			release:   release,
+			want:      "# Changelog\n\n## [Unreleased]\n\n- Pending\n\n" + section,
+		},
+		{
+			name:      "replaces Unreleased with Unreleased",
+			changelog: "# Changelog\n\n## [Unreleased]\n\n- Old\n\n## [1.0.0]\n\n- First\n",
+			release:   unreleased,
+			want:      "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Pending\n\n## [1.0.0]\n\n- First\n",
+		},
+		{
+			name:      "Unreleased above every release",
+			changelog: "# Changelog\n\n## [1.0.0]\n\n- First\n",
+			release:   unreleased,
+			want:      "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Pending\n\n## [1.0.0]\n\n- First\n",
+		},
+		{
+			name:      "replaces the section of the same version",
+			changelog: "# Changelog\n\n## [1.1.0] - 2024-04-01\n\n### Fixed\n\n- Old fix\n\n## [1.0.0]\n\n- First\n",
+			release:   release,
+			want:      "# Changelog\n\n" + section + "\n## [1.0.0]\n\n- First\n",
+		},
+		{
+			name:      "keeps link references at the end",
+			changelog: "# Changelog\n\n## [1.1.0]\n\n- Old fix\n\n[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0\n",
+			release:   release,
+			want:      "# Changelog\n\n" + section + "\n[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0\n",
+		},
+		{
+			name:      "no release yet",
+			changelog: "# Changelog\n\nNotable changes.\n",
+			release:   release,
+			want:      "# Changelog\n\nNotable changes.\n\n" + section,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Update(tt.changelog, tt.release); got != tt.want {
+				t.Errorf("Update() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionVersion(t *testing.T) {
+	tests := map[string]string{
+		"## [1.2.0] - 2024-01-01\n": "1.2.0",
+		"## [Unreleased]\n":         Unreleased,
+		"## 1.2.0 - 2024-01-01\n":   "1.2.0",
+		"## v2.0.0\n":               "v2.0.0",
+	}
+	for heading, want := range tests {
+		if got := sectionVersion(heading); got != want {
+			t.Errorf("sectionVersion(%q) = %q, want %q", heading, got, want)
+		}
+	}
+}
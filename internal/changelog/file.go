@@ -0,0 +1,112 @@
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// header starts a new changelog file
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+`
+
+// linkReference matches Markdown link definitions such as "[1.0.0]: https://..."
+var linkReference = regexp.MustCompile(`^\[[^\]]+\]: \S`)
+
+// Update returns changelog with the section of release replaced, or inserted
+// above the previous releases if the changelog has no section for its version.
+// A new tagged version replaces the Unreleased section, whose changes it now
+// lists.
+func Update(changelog string, release *Release) string {
+	var section strings.Builder
+	release.RenderMarkdown(&section)
+
+	if strings.TrimSpace(changelog) == "" {
+		return header + section.String()
+	}
+
+	lines := strings.SplitAfter(changelog, "\n")
+	start, end := findSection(lines, release.Version)
+	if start < 0 && release.Version != Unreleased {
+		start, end = findSection(lines, Unreleased)
+	}
+	if start < 0 {
+		// Insert above the first release
+		start = len(lines)
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				start = i
+				break
+			}
+		}
+		end = start
+	}
+
+	before := strings.Join(lines[:start], "")
+	after := strings.TrimLeft(strings.Join(lines[end:], ""), "\n")
+	if before != "" && !strings.HasSuffix(before, "\n\n") {
+		before = strings.TrimRight(before, "\n") + "\n\n"
+	}
+	if after != "" {
+		return before + section.String() + "\n" + after
+	}
+	return before + section.String()
+}
+
+// findSection returns the range of lines of the section for version, or -1
+// if there is none. Link reference definitions at the end of the file are
+// not part of the last section.
+func findSection(lines []string, version string) (int, int) {
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if start >= 0 {
+			return start, i
+		}
+		if sectionVersion(line) == version {
+			start = i
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	for end > start+1 && (linkReference.MatchString(lines[end-1]) || strings.TrimSpace(lines[end-1]) == "") {
+		end--
+	}
+	return start, end
+}
+
+// UpdateFile updates the changelog at path, creating it if it does not exist
+func UpdateFile(path string, release *Release) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, []byte(Update(string(data), release)), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// sectionVersion returns the version of a "## [1.2.0] - 2024-01-01" heading
+func sectionVersion(heading string) string {
+	heading = strings.TrimSpace(strings.TrimPrefix(heading, "## "))
+	if rest, ok := strings.CutPrefix(heading, "["); ok {
+		version, _, _ := strings.Cut(rest, "]")
+		return version
+	}
+	version, _, _ := strings.Cut(heading, " ")
+	return version
+}
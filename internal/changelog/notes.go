@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/conventional"
+	"github.com/richardamare/gitai/internal/git"
+)
+
+// MergeRequest is a merged merge request recovered from its merge commit
+type MergeRequest struct {
+	// Ref is the forge reference, e.g. "!12" or "#12"
+	Ref   string
+	Title string
+}
+
+var (
+	// Merge pull request #12 from owner/branch
+	githubMerge = regexp.MustCompile(`^Merge pull request (#\d+) from \S+`)
+	// Merge pull request 'Title' (#12) from branch into main
+	giteaMerge = regexp.MustCompile(`^Merge pull request '(.+)' \((#\d+)\) from `)
+	// Merge pull request #12 in PROJ/repo from branch to main
+	bitbucketMerge = regexp.MustCompile(`^Merge pull request (#\d+) in \S+ from `)
+	// See merge request group/project!12
+	gitlabMerge = regexp.MustCompile(`(?m)^See merge request \S*?(![0-9]+)\s*$`)
+)
+
+// MergedRequests returns the merge requests merged by the given merge
+// commits, recognising the default merge messages of GitHub, GitLab, Gitea
+// and Bitbucket Server. Other merges are skipped.
+func MergedRequests(merges []git.Commit) []MergeRequest {
+	var requests []MergeRequest
+	for _, merge := range merges {
+		if match := giteaMerge.FindStringSubmatch(merge.Subject); match != nil {
+			requests = append(requests, MergeRequest{Ref: match[2], Title: match[1]})
+			continue
+		}
+
+		var ref string
+		if match := githubMerge.FindStringSubmatch(merge.Subject); match != nil {
+			ref = match[1]
+		} else if match := bitbucketMerge.FindStringSubmatch(merge.Subject); match != nil {
+			ref = match[1]
+		} else if match := gitlabMerge.FindStringSubmatch(merge.Body); match != nil {
+			ref = match[1]
+		} else {
+			continue
+		}
+
+		// The title is the first body line, unless the body only lists commits
+		title := firstLine(merge.Body)
+		if title == "" || strings.HasPrefix(title, "* ") || gitlabMerge.MatchString(title) {
+			title = merge.Subject
+		}
+		requests = append(requests, MergeRequest{Ref: ref, Title: strings.TrimSpace(title)})
+	}
+	return requests
+}
+
+// NotesInput describes commits and merged merge requests as the input for
+// generating release notes. Breaking changes are marked so they are not
+// missed.
+func NotesInput(commits []git.Commit, merged []MergeRequest) string {
+	var b strings.Builder
+	if len(merged) > 0 {
+		fmt.Fprintln(&b, "Merged merge requests:")
+		for _, mr := range merged {
+			fmt.Fprintf(&b, "- %s %s\n", mr.Ref, mr.Title)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintln(&b, "Commits:")
+	for _, commit := range commits {
+		parsed := conventional.Parse(commit.Message())
+		marker := ""
+		if parsed.Breaking {
+			marker = "[BREAKING] "
+		}
+		fmt.Fprintf(&b, "- %s%s\n", marker, commit.Subject)
+		if parsed.BreakingNote != "" {
+			fmt.Fprintf(&b, "  BREAKING CHANGE: %s\n", parsed.BreakingNote)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// firstLine returns text up to the first newline
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
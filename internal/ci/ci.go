@@ -0,0 +1,150 @@
+package ci
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/forge"
+)
+
+// ErrNotDetected is returned by Detect outside a supported CI system
+var ErrNotDetected = errors.New("no supported CI environment detected")
+
+// Environment describes the merge request a pipeline runs for
+type Environment struct {
+	// Name is the CI system, e.g. "GitLab CI"
+	Name string
+	// Forge is the forge backend hosting the repository, one of forge.Types
+	Forge string
+	// ServerURL is the web URL of the forge instance
+	ServerURL string
+	// Project is the repository path on the forge, e.g. "group/project"
+	Project string
+	// Base is the commit or ref the merge request's changes are compared against
+	Base string
+	// Head is the commit under test; empty means HEAD
+	Head string
+	// BaseSHA and HeadSHA are the merge request's base and head commits as
+	// the forge reports them, if the CI system passes them on
+	BaseSHA string
+	HeadSHA string
+	// SourceBranch and TargetBranch are empty outside merge request pipelines
+	SourceBranch string
+	TargetBranch string
+	// MergeRequest is the merge request number, or 0 outside merge request pipelines
+	MergeRequest int
+	// MergeRequestURL is the web URL of the merge request, if known
+	MergeRequestURL string
+}
+
+// Detect reads the CI environment from the variables set by GitLab CI,
+// GitHub Actions and Gitea Actions
+func Detect() (*Environment, error) {
+	switch {
+	case os.Getenv("GITLAB_CI") == "true":
+		return detectGitLab(), nil
+	case os.Getenv("GITEA_ACTIONS") == "true":
+		// Gitea Actions sets the GitHub Actions variables as well
+		env, err := detectGitHub()
+		if err != nil {
+			return nil, err
+		}
+		env.Name = "Gitea Actions"
+		env.Forge = forge.TypeGitea
+		return env, nil
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return detectGitHub()
+	default:
+		return nil, ErrNotDetected
+	}
+}
+
+// detectGitLab reads the predefined GitLab CI/CD variables
+func detectGitLab() *Environment {
+	env := &Environment{
+		Name:         "GitLab CI",
+		Forge:        forge.TypeGitLab,
+		ServerURL:    os.Getenv("CI_SERVER_URL"),
+		Project:      cmp.Or(os.Getenv("CI_MERGE_REQUEST_PROJECT_PATH"), os.Getenv("CI_PROJECT_PATH")),
+		Base:         os.Getenv("CI_MERGE_REQUEST_DIFF_BASE_SHA"),
+		Head:         os.Getenv("CI_COMMIT_SHA"),
+		SourceBranch: os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"),
+		TargetBranch: os.Getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME"),
+	}
+	if iid, err := strconv.Atoi(os.Getenv("CI_MERGE_REQUEST_IID")); err == nil {
+		env.MergeRequest = iid
+		if projectURL := os.Getenv("CI_MERGE_REQUEST_PROJECT_URL"); projectURL != "" {
+			env.MergeRequestURL = fmt.Sprintf("%s/-/merge_requests/%d", projectURL, iid)
+		}
+	}
+	return env
+}
+
+// githubEvent holds the fields gitai uses from the workflow event payload
+type githubEvent struct {
+	PullRequest *struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+		Base    struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+// detectGitHub reads the default GitHub Actions variables and the
+// pull_request payload of the triggering event
+func detectGitHub() (*Environment, error) {
+	env := &Environment{
+		Name:         "GitHub Actions",
+		Forge:        forge.TypeGitHub,
+		ServerURL:    os.Getenv("GITHUB_SERVER_URL"),
+		Project:      os.Getenv("GITHUB_REPOSITORY"),
+		Head:         os.Getenv("GITHUB_SHA"),
+		SourceBranch: os.Getenv("GITHUB_HEAD_REF"),
+		TargetBranch: os.Getenv("GITHUB_BASE_REF"),
+	}
+
+	if path := os.Getenv("GITHUB_EVENT_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read workflow event: %w", err)
+		}
+		var event githubEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse workflow event: %w", err)
+		}
+		if pr := event.PullRequest; pr != nil {
+			env.MergeRequest = pr.Number
+			env.MergeRequestURL = pr.HTMLURL
+			env.Base = pr.Base.SHA
+			// GITHUB_SHA is the synthetic merge commit, which the pull
+			// request does not contain
+			env.Head = cmp.Or(pr.Head.SHA, env.Head)
+			env.BaseSHA, env.HeadSHA = pr.Base.SHA, pr.Head.SHA
+			env.SourceBranch = cmp.Or(env.SourceBranch, pr.Head.Ref)
+			env.TargetBranch = cmp.Or(env.TargetBranch, pr.Base.Ref)
+		}
+	}
+
+	// Pull request workflows run on refs/pull/<number>/merge
+	if env.MergeRequest == 0 {
+		if rest, ok := strings.CutPrefix(os.Getenv("GITHUB_REF"), "refs/pull/"); ok {
+			number, _, _ := strings.Cut(rest, "/")
+			env.MergeRequest, _ = strconv.Atoi(number)
+		}
+	}
+	if env.Base == "" && env.TargetBranch != "" {
+		env.Base = "origin/" + env.TargetBranch
+	}
+	return env, nil
+}
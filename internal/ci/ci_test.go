@@ -0,0 +1,36 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectGitHubPullRequest(t *testing.T) {
+	event := filepath.Join(t.TempDir(), "event.json")
+	payload := `{"pull_request": {"number": 7, "html_url": "https://github.com/owner/repo/pull/7",
+		"base": {"ref": "main", "sha": "base123"}, "head": {"ref": "feature", "sha": "head123"}}}`
+	if err := os.WriteFile(event, []byte(payload), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITLAB_CI", "")
+	t.Setenv("GITEA_ACTIONS", "")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_EVENT_PATH", event)
+	t.Setenv("GITHUB_SHA", "merge123")
+	t.Setenv("GITHUB_REF", "refs/pull/7/merge")
+	t.Setenv("GITHUB_HEAD_REF", "feature")
+	t.Setenv("GITHUB_BASE_REF", "main")
+
+	env, err := Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.MergeRequest != 7 || env.Base != "base123" || env.BaseSHA != "base123" {
+		t.Errorf("Detect() = %+v", env)
+	}
+	// The synthetic merge commit in GITHUB_SHA is not part of the pull request
+	if env.Head != "head123" || env.HeadSHA != "head123" {
+		t.Errorf("Head = %q, HeadSHA = %q, want the pull request's head", env.Head, env.HeadSHA)
+	}
+}
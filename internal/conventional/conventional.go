@@ -0,0 +1,107 @@
+package conventional
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commit is a commit message parsed according to the Conventional Commits
+// specification
+type Commit struct {
+	// Type is the lowercase commit type, e.g. "feat"; empty if the header does
+	// not follow the specification
+	Type        string
+	Scope       string
+	Description string
+	Body        string
+	Footers     []Footer
+	// Breaking is set by a "!" after the type or scope, or a BREAKING CHANGE footer
+	Breaking bool
+	// BreakingNote is the text of the BREAKING CHANGE footer, if any
+	BreakingNote string
+}
+
+// Footer is a trailer such as "Refs: #123" or "BREAKING CHANGE: ..."
+type Footer struct {
+	Token string
+	Value string
+}
+
+var (
+	headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: +(.+)$`)
+	footerPattern = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][A-Za-z-]*)(?:: | #)(.*)$`)
+)
+
+// Parse parses a commit message. Messages whose header does not follow the
+// specification are returned with an empty Type and the header as Description.
+func Parse(message string) Commit {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	header, rest, _ := strings.Cut(message, "\n")
+	header = strings.TrimSpace(header)
+
+	match := headerPattern.FindStringSubmatch(header)
+	if match == nil {
+		return Commit{Description: header, Body: strings.TrimSpace(rest)}
+	}
+
+	commit := Commit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Breaking:    match[3] == "!",
+		Description: strings.TrimSpace(match[4]),
+	}
+	commit.Body, commit.Footers = splitFooters(strings.TrimSpace(rest))
+
+	for _, footer := range commit.Footers {
+		if IsBreakingToken(footer.Token) {
+			commit.Breaking = true
+			commit.BreakingNote = footer.Value
+		}
+	}
+	return commit
+}
+
+// Conventional reports whether the header followed the specification
+func (c Commit) Conventional() bool {
+	return c.Type != ""
+}
+
+// IsBreakingToken reports whether a footer token announces a breaking change
+func IsBreakingToken(token string) bool {
+	return token == "BREAKING CHANGE" || token == "BREAKING-CHANGE"
+}
+
+// splitFooters separates the trailing footer paragraph from the body.
+// Lines that do not start a new footer continue the previous one.
+func splitFooters(text string) (string, []Footer) {
+	if text == "" {
+		return "", nil
+	}
+
+	start := 0
+	if i := strings.LastIndex(text, "\n\n"); i >= 0 {
+		start = i + 2
+	}
+	paragraph := text[start:]
+	if !footerPattern.MatchString(firstLine(paragraph)) {
+		return text, nil
+	}
+
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if match := footerPattern.FindStringSubmatch(line); match != nil {
+			footers = append(footers, Footer{Token: match[1], Value: strings.TrimSpace(match[2])})
+			continue
+		}
+		last := &footers[len(footers)-1]
+		last.Value = strings.TrimSpace(last.Value + "\n" + line)
+	}
+
+	return strings.TrimSpace(text[:start]), footers
+}
+
+// firstLine returns text up to the first newline
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,111 @@
+package conventional
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    Commit
+	}{
+		{
+			name:    "type only",
+			message: "fix: handle empty diffs",
+			want:    Commit{Type: "fix", Description: "handle empty diffs"},
+		},
+		{
+			name:    "scope and upper-case type",
+			message: "Feat(api): add pagination",
+			want:    Commit{Type: "feat", Scope: "api", Description: "add pagination"},
+		},
+		{
+			name:    "bang",
+			message: "feat(api)!: remove the v1 endpoints",
+			want:    Commit{Type: "feat", Scope: "api", Description: "remove the v1 endpoints", Breaking: true},
+		},
+		{
+			name:    "bang without scope",
+			message: "refactor!: rename config keys",
+			want:    Commit{Type: "refactor", Description: "rename config keys", Breaking: true},
+		},
+		{
+			name:    "breaking change footer",
+			message: "feat: new config format\n\nConfig is now YAML.\n\nBREAKING CHANGE: the TOML config is no longer read\nRefs: #42",
+			want: Commit{
+				Type:        "feat",
+				Description: "new config format",
+				Body:        "Config is now YAML.",
+				Footers: []Footer{
+					{Token: "BREAKING CHANGE", Value: "the TOML config is no longer read"},
+					{Token: "Refs", Value: "#42"},
+				},
+				Breaking:     true,
+				BreakingNote: "the TOML config is no longer read",
+			},
+		},
+		{
+			name:    "hyphenated breaking change footer",
+			message: "fix: stricter parsing\n\nBREAKING-CHANGE: invalid dates are rejected",
+			want: Commit{
+				Type:         "fix",
+				Description:  "stricter parsing",
+				Footers:      []Footer{{Token: "BREAKING-CHANGE", Value: "invalid dates are rejected"}},
+				Breaking:     true,
+				BreakingNote: "invalid dates are rejected",
+			},
+		},
+		{
+			name:    "multi-line footer",
+			message: "fix: x\n\nBREAKING CHANGE: first line\nsecond line\nCloses #7",
+			want: Commit{
+				Type:        "fix",
+				Description: "x",
+				Footers: []Footer{
+					{Token: "BREAKING CHANGE", Value: "first line\nsecond line"},
+					{Token: "Closes", Value: "7"},
+				},
+				Breaking:     true,
+				BreakingNote: "first line\nsecond line",
+			},
+		},
+		{
+			name:    "lower-case breaking change is not a breaking footer",
+			message: "fix: x\n\nbreaking change: not a footer token",
+			want:    Commit{Type: "fix", Description: "x", Body: "breaking change: not a footer token"},
+		},
+		{
+			name:    "body without footers",
+			message: "docs: explain caching\n\nFirst paragraph.\n\nSecond paragraph.",
+			want:    Commit{Type: "docs", Description: "explain caching", Body: "First paragraph.\n\nSecond paragraph."},
+		},
+		{
+			name:    "CRLF line endings",
+			message: "fix: windows\r\n\r\nReviewed-by: Jane\r\n",
+			want:    Commit{Type: "fix", Description: "windows", Footers: []Footer{{Token: "Reviewed-by", Value: "Jane"}}},
+		},
+		{
+			name:    "not conventional",
+			message: "Update README\n\nMore details.",
+			want:    Commit{Description: "Update README", Body: "More details."},
+		},
+		{
+			name:    "missing space after colon",
+			message: "fix:no space",
+			want:    Commit{Description: "fix:no space"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.message)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.message, got, tt.want)
+			}
+			if got.Conventional() != (tt.want.Type != "") {
+				t.Errorf("Conventional() = %v", got.Conventional())
+			}
+		})
+	}
+}
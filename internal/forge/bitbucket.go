@@ -0,0 +1,254 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// BitbucketServer talks to the Bitbucket Server (Data Center) REST API for a
+// single repository
+type BitbucketServer struct {
+	api     *apiClient
+	project string
+	slug    string
+}
+
+// NewBitbucketServer creates a Bitbucket Server client for repository on the
+// instance at baseURL. repository is the remote path, e.g. "scm/PROJ/repo"
+// for HTTP clones or "proj/repo" for SSH clones.
+func NewBitbucketServer(baseURL, token, repository string) (*BitbucketServer, error) {
+	parts := strings.Split(strings.TrimPrefix(repository, "scm/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid Bitbucket Server repository %q, expected PROJECT/repo", repository)
+	}
+
+	// Project keys are upper case, except personal projects such as ~user
+	project := parts[0]
+	if !strings.HasPrefix(project, "~") {
+		project = strings.ToUpper(project)
+	}
+
+	return &BitbucketServer{
+		api:     newAPIClient(strings.TrimSuffix(baseURL, "/")+"/rest/api/1.0", map[string]string{"Authorization": "Bearer " + token}),
+		project: project,
+		slug:    parts[1],
+	}, nil
+}
+
+type bitbucketRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId,omitempty"`
+	LatestCommit string `json:"latestCommit,omitempty"`
+}
+
+type bitbucketPullRequest struct {
+	ID          int          `json:"id"`
+	Version     int          `json:"version"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	FromRef     bitbucketRef `json:"fromRef"`
+	ToRef       bitbucketRef `json:"toRef"`
+	Links       struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+func (p bitbucketPullRequest) toMergeRequest() *MergeRequest {
+	mr := &MergeRequest{
+		Number:       p.ID,
+		Title:        p.Title,
+		Description:  p.Description,
+		SourceBranch: p.FromRef.DisplayID,
+		TargetBranch: p.ToRef.DisplayID,
+		BaseSHA:      p.ToRef.LatestCommit,
+		HeadSHA:      p.FromRef.LatestCommit,
+	}
+	if len(p.Links.Self) > 0 {
+		mr.URL = p.Links.Self[0].Href
+	}
+	return mr
+}
+
+func (b *BitbucketServer) repoPath() string {
+	return "/projects/" + url.PathEscape(b.project) + "/repos/" + url.PathEscape(b.slug)
+}
+
+func (b *BitbucketServer) pullRequestPath(mr *MergeRequest) string {
+	return fmt.Sprintf("%s/pull-requests/%d", b.repoPath(), mr.Number)
+}
+
+// FindMergeRequest returns the open pull request for branch, or nil if there is none
+func (b *BitbucketServer) FindMergeRequest(branch string) (*MergeRequest, error) {
+	query := url.Values{"at": {"refs/heads/" + branch}, "direction": {"OUTGOING"}, "state": {"OPEN"}}
+	var page struct {
+		Values []bitbucketPullRequest `json:"values"`
+	}
+	if err := b.api.do(http.MethodGet, b.repoPath()+"/pull-requests?"+query.Encode(), nil, &page); err != nil {
+		return nil, fmt.Errorf("failed to find pull request for %s: %w", branch, err)
+	}
+	if len(page.Values) == 0 {
+		return nil, nil
+	}
+	return page.Values[0].toMergeRequest(), nil
+}
+
+// UpdateMergeRequest sets the title and, if non-empty, the description of the pull request
+func (b *BitbucketServer) UpdateMergeRequest(mr *MergeRequest, title, description string) (*MergeRequest, error) {
+	// Updates must carry the current version to guard against concurrent edits
+	var current bitbucketPullRequest
+	if err := b.api.do(http.MethodGet, b.pullRequestPath(mr), nil, &current); err != nil {
+		return nil, fmt.Errorf("failed to get pull request %d: %w", mr.Number, err)
+	}
+	if description == "" {
+		description = current.Description
+	}
+
+	body := map[string]any{
+		"version":     current.Version,
+		"title":       title,
+		"description": description,
+		"toRef":       bitbucketRef{ID: current.ToRef.ID},
+	}
+	var updated bitbucketPullRequest
+	if err := b.api.do(http.MethodPut, b.pullRequestPath(mr), body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update pull request %d: %w", mr.Number, err)
+	}
+	return updated.toMergeRequest(), nil
+}
+
+// CreateMergeRequest opens a pull request from source into target
+func (b *BitbucketServer) CreateMergeRequest(source, target, title, description string) (*MergeRequest, error) {
+	body := map[string]any{
+		"title":       title,
+		"description": description,
+		"fromRef":     bitbucketRef{ID: "refs/heads/" + source},
+		"toRef":       bitbucketRef{ID: "refs/heads/" + target},
+	}
+
+	var created bitbucketPullRequest
+	if err := b.api.do(http.MethodPost, b.repoPath()+"/pull-requests", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return created.toMergeRequest(), nil
+}
+
+// DefaultBranch returns the repository's default branch
+func (b *BitbucketServer) DefaultBranch() (string, error) {
+	var branch bitbucketRef
+	if err := b.api.do(http.MethodGet, b.repoPath()+"/branches/default", nil, &branch); err != nil {
+		return "", fmt.Errorf("failed to get default branch: %w", err)
+	}
+	return branch.DisplayID, nil
+}
+
+type bitbucketComment struct {
+	ID             int    `json:"id"`
+	Version        int    `json:"version"`
+	Text           string `json:"text"`
+	ThreadResolved bool   `json:"threadResolved"`
+}
+
+type bitbucketAnchor struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	LineType string `json:"lineType"`
+	FileType string `json:"fileType"`
+	DiffType string `json:"diffType"`
+}
+
+// PostReview adds a comment for every finding gitai has no open thread for,
+// anchored to its diff line where possible, and resolves the threads gitai
+// started for findings that are no longer reported
+func (b *BitbucketServer) PostReview(mr *MergeRequest, findings []Finding) (*ReviewResult, error) {
+	existing, err := b.comments(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	posted := map[string]bool{}
+	for _, comment := range existing {
+		// A finding whose thread was resolved is posted again
+		if comment.ThreadResolved {
+			continue
+		}
+		for _, fingerprint := range markers(comment.Text) {
+			posted[fingerprint] = true
+		}
+	}
+
+	result := &ReviewResult{}
+	for _, finding := range findings {
+		if posted[finding.Fingerprint] {
+			result.Duplicates++
+			continue
+		}
+		posted[finding.Fingerprint] = true
+
+		body := map[string]any{"text": findingBody(finding)}
+		if finding.Position != nil {
+			lineType := "CONTEXT"
+			if finding.Position.OldLine == 0 {
+				lineType = "ADDED"
+			}
+			body["anchor"] = bitbucketAnchor{
+				Path:     finding.File,
+				Line:     finding.Position.NewLine,
+				LineType: lineType,
+				FileType: "TO",
+				DiffType: "EFFECTIVE",
+			}
+		}
+		if err := b.api.do(http.MethodPost, b.pullRequestPath(mr)+"/comments", body, nil); err != nil {
+			return nil, fmt.Errorf("failed to post finding on pull request %d: %w", mr.Number, err)
+		}
+		result.Posted++
+	}
+
+	current := fingerprints(findings)
+	for _, comment := range existing {
+		stale := markers(comment.Text)
+		if comment.ThreadResolved || len(stale) == 0 || current[stale[0]] {
+			continue
+		}
+		body := map[string]any{"version": comment.Version, "threadResolved": true}
+		if err := b.api.do(http.MethodPut, fmt.Sprintf("%s/comments/%d", b.pullRequestPath(mr), comment.ID), body, nil); err != nil {
+			return nil, fmt.Errorf("failed to resolve comment on pull request %d: %w", mr.Number, err)
+		}
+		result.Resolved++
+	}
+
+	return result, nil
+}
+
+// comments returns the top-level comments of the pull request
+func (b *BitbucketServer) comments(mr *MergeRequest) ([]bitbucketComment, error) {
+	var comments []bitbucketComment
+	start := 0
+	for {
+		var page struct {
+			Values []struct {
+				Action  string            `json:"action"`
+				Comment *bitbucketComment `json:"comment"`
+			} `json:"values"`
+			IsLastPage    bool `json:"isLastPage"`
+			NextPageStart int  `json:"nextPageStart"`
+		}
+		path := fmt.Sprintf("%s/activities?limit=100&start=%d", b.pullRequestPath(mr), start)
+		if err := b.api.do(http.MethodGet, path, nil, &page); err != nil {
+			return nil, fmt.Errorf("failed to list activity of pull request %d: %w", mr.Number, err)
+		}
+		for _, activity := range page.Values {
+			if activity.Action == "COMMENTED" && activity.Comment != nil {
+				comments = append(comments, *activity.Comment)
+			}
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			return comments, nil
+		}
+		start = page.NextPageStart
+	}
+}
@@ -0,0 +1,154 @@
+package forge
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+func TestNewBitbucketServerProjectKey(t *testing.T) {
+	tests := []struct {
+		repository string
+		project    string
+	}{
+		{"scm/proj/repo", "PROJ"},
+		{"proj/repo", "PROJ"},
+		{"scm/~jdoe/repo", "~jdoe"},
+		{"~jdoe/repo", "~jdoe"},
+	}
+	for _, tt := range tests {
+		b, err := NewBitbucketServer("https://bitbucket.example.com", "token", tt.repository)
+		if err != nil {
+			t.Fatalf("NewBitbucketServer(%q) = %v", tt.repository, err)
+		}
+		if b.project != tt.project || b.slug != "repo" {
+			t.Errorf("NewBitbucketServer(%q) = %s/%s, want %s/repo", tt.repository, b.project, b.slug, tt.project)
+		}
+	}
+
+	if _, err := NewBitbucketServer("https://bitbucket.example.com", "token", "scm/repo"); err == nil {
+		t.Error("NewBitbucketServer(scm/repo) succeeded")
+	}
+}
+
+func TestBitbucketServerMergeRequests(t *testing.T) {
+	api := newFakeAPI(t)
+	const prs = "/rest/api/1.0/projects/~jdoe/repos/repo/pull-requests"
+	pr := map[string]any{
+		"id":          7,
+		"version":     3,
+		"title":       "Old title",
+		"description": "Old description",
+		"fromRef":     map[string]any{"id": "refs/heads/feature", "displayId": "feature", "latestCommit": "head123"},
+		"toRef":       map[string]any{"id": "refs/heads/main", "displayId": "main", "latestCommit": "base123"},
+		"links":       map[string]any{"self": []map[string]string{{"href": "https://bitbucket.example.com/pr/7"}}},
+	}
+	api.reply(http.MethodGet, prs, http.StatusOK, map[string]any{"values": []any{pr}})
+	api.reply(http.MethodGet, prs+"/7", http.StatusOK, pr)
+	api.reply(http.MethodPut, prs+"/7", http.StatusOK, pr)
+	api.reply(http.MethodPost, prs, http.StatusCreated, pr)
+
+	b, err := NewBitbucketServer(api.URL(), "secret", "~jdoe/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr, err := b.FindMergeRequest("feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MergeRequest{
+		Number: 7, Title: "Old title", Description: "Old description", URL: "https://bitbucket.example.com/pr/7",
+		SourceBranch: "feature", TargetBranch: "main", BaseSHA: "base123", HeadSHA: "head123",
+	}
+	if *mr != want {
+		t.Errorf("FindMergeRequest() = %+v, want %+v", *mr, want)
+	}
+	find := api.sent(http.MethodGet, prs)[0]
+	if find.Query.Get("at") != "refs/heads/feature" || find.Query.Get("state") != "OPEN" {
+		t.Errorf("FindMergeRequest() query = %v", find.Query)
+	}
+	if find.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Authorization = %q", find.Header.Get("Authorization"))
+	}
+
+	// An empty description keeps the current one, and the update carries
+	// the current version
+	if _, err := b.UpdateMergeRequest(mr, "New title", ""); err != nil {
+		t.Fatal(err)
+	}
+	update := api.sent(http.MethodPut, prs+"/7")[0].Body
+	if update["title"] != "New title" || update["description"] != "Old description" || update["version"] != float64(3) {
+		t.Errorf("UpdateMergeRequest() sent %v", update)
+	}
+
+	if _, err := b.CreateMergeRequest("feature", "main", "Title", "Body"); err != nil {
+		t.Fatal(err)
+	}
+	create := api.sent(http.MethodPost, prs)[0].Body
+	if create["fromRef"].(map[string]any)["id"] != "refs/heads/feature" || create["toRef"].(map[string]any)["id"] != "refs/heads/main" {
+		t.Errorf("CreateMergeRequest() sent %v", create)
+	}
+}
+
+func TestBitbucketServerPostReview(t *testing.T) {
+	api := newFakeAPI(t)
+	const pr = "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests/7"
+	comment := func(id int, fingerprint string, resolved bool) map[string]any {
+		return map[string]any{
+			"action":  "COMMENTED",
+			"comment": map[string]any{"id": id, "version": 1, "text": "finding\n\n" + marker(fingerprint), "threadResolved": resolved},
+		}
+	}
+	api.reply(http.MethodGet, pr+"/activities", http.StatusOK, map[string]any{
+		"values": []any{
+			comment(1, "aaaa", false),
+			comment(2, "bbbb", true),
+			comment(3, "cccc", false),
+			map[string]any{"action": "APPROVED"},
+		},
+		"isLastPage": true,
+	})
+	api.reply(http.MethodPost, pr+"/comments", http.StatusCreated, map[string]any{})
+	api.reply(http.MethodPut, pr+"/comments/3", http.StatusOK, map[string]any{})
+
+	b, err := NewBitbucketServer(api.URL(), "secret", "proj/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []Finding{
+		// Still open: a duplicate
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 1, Comment: "open"}, Fingerprint: "aaaa"},
+		// Resolved before and reported again: posted again
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 2, Comment: "reopened"}, Fingerprint: "bbbb"},
+		// New and anchored in the diff
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 3, Comment: "new"}, Fingerprint: "dddd", Position: &git.LinePosition{NewLine: 3}},
+	}
+	result, err := b.PostReview(&MergeRequest{Number: 7}, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (ReviewResult{Posted: 2, Duplicates: 1, Resolved: 1}) {
+		t.Errorf("PostReview() = %+v", *result)
+	}
+
+	posted := api.sent(http.MethodPost, pr+"/comments")
+	if len(posted) != 2 {
+		t.Fatalf("posted %d comments, want 2", len(posted))
+	}
+	if _, ok := posted[0].Body["anchor"]; ok {
+		t.Errorf("finding outside the diff was anchored: %v", posted[0].Body)
+	}
+	anchor, _ := posted[1].Body["anchor"].(map[string]any)
+	if anchor["path"] != "a.go" || anchor["line"] != float64(3) || anchor["lineType"] != "ADDED" {
+		t.Errorf("anchor = %v", anchor)
+	}
+
+	resolve := api.sent(http.MethodPut, pr+"/comments/3")
+	if len(resolve) != 1 || resolve[0].Body["threadResolved"] != true {
+		t.Errorf("stale thread not resolved: %+v", resolve)
+	}
+}
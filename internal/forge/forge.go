@@ -0,0 +1,120 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// Forge is a code hosting service gitai can publish merge requests to
+type Forge interface {
+	// FindMergeRequest returns the open merge request for branch, or nil if there is none
+	FindMergeRequest(branch string) (*MergeRequest, error)
+	// UpdateMergeRequest sets the title and, if non-empty, the description of mr
+	UpdateMergeRequest(mr *MergeRequest, title, description string) (*MergeRequest, error)
+	// CreateMergeRequest opens a merge request from source into target
+	CreateMergeRequest(source, target, title, description string) (*MergeRequest, error)
+	// DefaultBranch returns the repository's default branch
+	DefaultBranch() (string, error)
+}
+
+// ReviewPoster is implemented by forges that can publish review findings on a merge request
+type ReviewPoster interface {
+	// PostReview publishes findings that gitai has not posted before and
+	// resolves threads gitai opened for findings that are no longer reported
+	PostReview(mr *MergeRequest, findings []Finding) (*ReviewResult, error)
+}
+
+// Backend names accepted by New
+const (
+	TypeGitHub    = "github"
+	TypeGitLab    = "gitlab"
+	TypeGitea     = "gitea"
+	TypeBitbucket = "bitbucket"
+)
+
+// Types lists the supported backends
+var Types = []string{TypeGitHub, TypeGitLab, TypeGitea, TypeBitbucket}
+
+var (
+	_ ReviewPoster = (*GitHub)(nil)
+	_ ReviewPoster = (*GitLab)(nil)
+	_ ReviewPoster = (*Gitea)(nil)
+	_ ReviewPoster = (*BitbucketServer)(nil)
+)
+
+// Config describes how to reach a forge instance
+type Config struct {
+	// Type is the backend, one of Types
+	Type string `mapstructure:"type"`
+	// Host is the remote host the config applies to
+	Host string `mapstructure:"host"`
+	// URL is the web URL of the instance; defaults to https://<host>
+	URL   string `mapstructure:"url"`
+	Token string `mapstructure:"token"`
+}
+
+// New creates the backend described by cfg for the repository at path
+func New(cfg Config, path string) (Forge, error) {
+	if err := CheckType(cfg.Type); err != nil {
+		return nil, err
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("no token configured for %s forge at %s", cfg.Type, cfg.Host)
+	}
+
+	baseURL := cfg.URL
+	if baseURL == "" {
+		baseURL = "https://" + cfg.Host
+	}
+
+	switch cfg.Type {
+	case TypeGitHub:
+		return NewGitHub(GitHubAPIURL(baseURL), cfg.Token, path)
+	case TypeGitLab:
+		return NewGitLab(baseURL, cfg.Token, path), nil
+	case TypeGitea:
+		return NewGitea(baseURL, cfg.Token, path)
+	case TypeBitbucket:
+		return NewBitbucketServer(baseURL, cfg.Token, path)
+	default:
+		return nil, CheckType(cfg.Type)
+	}
+}
+
+// CheckType returns an error if kind is not one of Types
+func CheckType(kind string) error {
+	for _, t := range Types {
+		if t == kind {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown forge type %q: use %s", kind, strings.Join(Types, ", "))
+}
+
+// MergeRequest is an open merge or pull request on a forge
+type MergeRequest struct {
+	Number       int
+	Title        string
+	Description  string
+	URL          string
+	SourceBranch string
+	TargetBranch string
+	// BaseSHA and HeadSHA are the commits the forge compares to show the
+	// changes of the merge request
+	BaseSHA string
+	HeadSHA string
+}
+
+// FormatComment renders a review finding as a Markdown comment body
+func FormatComment(comment models.ReviewComment) string {
+	label := comment.Category
+	if comment.Severity != "" {
+		label += ", " + comment.Severity
+	}
+	if label == "" {
+		return comment.Comment
+	}
+	return fmt.Sprintf("**%s**: %s", strings.TrimPrefix(label, ", "), comment.Comment)
+}
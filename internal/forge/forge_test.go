@@ -0,0 +1,139 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAPI is a forge API served by httptest. Handlers are registered per
+// method and escaped path; every request is recorded.
+type fakeAPI struct {
+	t        *testing.T
+	server   *httptest.Server
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	requests []fakeRequest
+}
+
+type fakeRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   map[string]any
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	f := &fakeAPI{t: t, handlers: map[string]http.HandlerFunc{}}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// URL returns the base URL of the server
+func (f *fakeAPI) URL() string {
+	return f.server.URL
+}
+
+func (f *fakeAPI) handle(method, path string, handler http.HandlerFunc) {
+	f.handlers[method+" "+path] = handler
+}
+
+// reply serves body as JSON with status for method and path
+func (f *fakeAPI) reply(method, path string, status int, body any) {
+	f.handle(method, path, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, status, body)
+	})
+}
+
+func (f *fakeAPI) serve(w http.ResponseWriter, r *http.Request) {
+	req := fakeRequest{Method: r.Method, Path: r.URL.EscapedPath(), Query: r.URL.Query(), Header: r.Header}
+	data, _ := io.ReadAll(r.Body)
+	json.Unmarshal(data, &req.Body)
+	// Leave the body readable for the handler
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	handler, ok := f.handlers[r.Method+" "+req.Path]
+	f.mu.Unlock()
+
+	if !ok {
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+		return
+	}
+	handler(w, r)
+}
+
+// sent returns the recorded requests for method and path
+func (f *fakeAPI) sent(method, path string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []fakeRequest
+	for _, req := range f.requests {
+		if req.Method == method && req.Path == path {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+func readBody(r *http.Request) string {
+	data, _ := io.ReadAll(r.Body)
+	return string(data)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// marker returns the hidden marker gitai adds to the comment for fingerprint
+func marker(fingerprint string) string {
+	return "<!-- gitai:finding " + fingerprint + " -->"
+}
+
+func TestNewChecksTypeBeforeToken(t *testing.T) {
+	_, err := New(Config{Type: "gitlba", Host: "git.example.com"}, "group/project")
+	if err == nil || !strings.Contains(err.Error(), `unknown forge type "gitlba"`) {
+		t.Errorf("New() with an unknown type = %v, want an unknown type error", err)
+	}
+
+	_, err = New(Config{Type: TypeGitLab, Host: "git.example.com"}, "group/project")
+	if err == nil || !strings.Contains(err.Error(), "no token") {
+		t.Errorf("New() without a token = %v, want a missing token error", err)
+	}
+}
+
+func TestAPIErrorStatus(t *testing.T) {
+	api := newFakeAPI(t)
+	api.reply(http.MethodGet, "/missing", http.StatusNotFound, map[string]string{"message": "404 Not Found"})
+
+	err := newAPIClient(api.URL(), nil).do(http.MethodGet, "/missing", nil, nil)
+	if !hasStatus(err, http.StatusNotFound) || hasStatus(err, http.StatusBadRequest) {
+		t.Errorf("do() = %v, want a 404 API error", err)
+	}
+	if !strings.Contains(err.Error(), "GET /missing returned 404 Not Found") {
+		t.Errorf("do() error = %q, want the method, path and status", err)
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	header := http.Header{}
+	header.Set("Link", `<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=3>; rel="next", <https://api.github.com/x?page=5>; rel="last"`)
+	if got := nextLink(header); got != "https://api.github.com/x?page=3" {
+		t.Errorf("nextLink() = %q", got)
+	}
+	header.Set("Link", `<https://api.github.com/x?page=1>; rel="first"`)
+	if got := nextLink(header); got != "" {
+		t.Errorf("nextLink() on the last page = %q, want none", got)
+	}
+}
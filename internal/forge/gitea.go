@@ -0,0 +1,233 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Gitea talks to the Gitea REST API for a single repository
+type Gitea struct {
+	api   *apiClient
+	owner string
+	repo  string
+}
+
+// NewGitea creates a Gitea client for repository (e.g. "owner/repo") on the
+// instance at baseURL (e.g. "https://gitea.example.com")
+func NewGitea(baseURL, token, repository string) (*Gitea, error) {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("invalid Gitea repository %q, expected owner/repo", repository)
+	}
+
+	return &Gitea{
+		api:   newAPIClient(strings.TrimSuffix(baseURL, "/")+"/api/v1", map[string]string{"Authorization": "token " + token}),
+		owner: owner,
+		repo:  repo,
+	}, nil
+}
+
+type giteaPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"base"`
+}
+
+func (p giteaPullRequest) toMergeRequest() *MergeRequest {
+	return &MergeRequest{
+		Number:       p.Number,
+		Title:        p.Title,
+		Description:  p.Body,
+		URL:          p.HTMLURL,
+		SourceBranch: p.Head.Ref,
+		TargetBranch: p.Base.Ref,
+		BaseSHA:      p.Base.SHA,
+		HeadSHA:      p.Head.SHA,
+	}
+}
+
+func (g *Gitea) repoPath() string {
+	return "/repos/" + url.PathEscape(g.owner) + "/" + url.PathEscape(g.repo)
+}
+
+// FindMergeRequest returns the open pull request for branch, or nil if there is none
+func (g *Gitea) FindMergeRequest(branch string) (*MergeRequest, error) {
+	const limit = 50
+	for page := 1; ; page++ {
+		var prs []giteaPullRequest
+		path := fmt.Sprintf("%s/pulls?state=open&limit=%d&page=%d", g.repoPath(), limit, page)
+		if err := g.api.do(http.MethodGet, path, nil, &prs); err != nil {
+			return nil, fmt.Errorf("failed to find pull request for %s: %w", branch, err)
+		}
+		for _, pr := range prs {
+			if pr.Head.Ref == branch {
+				return pr.toMergeRequest(), nil
+			}
+		}
+		if len(prs) < limit {
+			return nil, nil
+		}
+	}
+}
+
+// UpdateMergeRequest sets the title and, if non-empty, the body of the pull request
+func (g *Gitea) UpdateMergeRequest(mr *MergeRequest, title, description string) (*MergeRequest, error) {
+	body := map[string]string{"title": title}
+	if description != "" {
+		body["body"] = description
+	}
+
+	var updated giteaPullRequest
+	if err := g.api.do(http.MethodPatch, fmt.Sprintf("%s/pulls/%d", g.repoPath(), mr.Number), body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update pull request #%d: %w", mr.Number, err)
+	}
+	return updated.toMergeRequest(), nil
+}
+
+// CreateMergeRequest opens a pull request from source into target
+func (g *Gitea) CreateMergeRequest(source, target, title, description string) (*MergeRequest, error) {
+	body := map[string]string{
+		"head":  source,
+		"base":  target,
+		"title": title,
+		"body":  description,
+	}
+
+	var created giteaPullRequest
+	if err := g.api.do(http.MethodPost, g.repoPath()+"/pulls", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return created.toMergeRequest(), nil
+}
+
+// DefaultBranch returns the repository's default branch
+func (g *Gitea) DefaultBranch() (string, error) {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := g.api.do(http.MethodGet, g.repoPath(), nil, &repo); err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	return repo.DefaultBranch, nil
+}
+
+type giteaReviewComment struct {
+	Path        string `json:"path"`
+	Body        string `json:"body"`
+	NewPosition int    `json:"new_position"`
+}
+
+// PostReview submits new findings as a single pull request review, with
+// findings anchored in the diff as line comments and the rest in the review
+// body. The Gitea API cannot resolve conversations, so stale findings are
+// left open.
+func (g *Gitea) PostReview(mr *MergeRequest, findings []Finding) (*ReviewResult, error) {
+	posted, err := g.postedFindings(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReviewResult{}
+	var body strings.Builder
+	body.WriteString("AI review by gitai")
+	comments := []giteaReviewComment{}
+	for _, finding := range findings {
+		if posted[finding.Fingerprint] {
+			result.Duplicates++
+			continue
+		}
+		posted[finding.Fingerprint] = true
+		result.Posted++
+
+		if finding.Position == nil {
+			fmt.Fprintf(&body, "\n\n%s", findingBody(finding))
+			continue
+		}
+		comments = append(comments, giteaReviewComment{
+			Path:        finding.File,
+			Body:        findingBody(finding),
+			NewPosition: finding.Position.NewLine,
+		})
+	}
+
+	if result.Posted == 0 {
+		return result, nil
+	}
+
+	payload := map[string]any{
+		"event":    "COMMENT",
+		"body":     body.String(),
+		"comments": comments,
+	}
+	if err := g.api.do(http.MethodPost, fmt.Sprintf("%s/pulls/%d/reviews", g.repoPath(), mr.Number), payload, nil); err != nil {
+		return nil, fmt.Errorf("failed to submit review on pull request #%d: %w", mr.Number, err)
+	}
+	return result, nil
+}
+
+// postedFindings returns the fingerprints of findings gitai already posted
+// in reviews of the pull request
+func (g *Gitea) postedFindings(mr *MergeRequest) (map[string]bool, error) {
+	prPath := fmt.Sprintf("%s/pulls/%d", g.repoPath(), mr.Number)
+	reviews, err := g.reviews(prPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews on pull request #%d: %w", mr.Number, err)
+	}
+
+	posted := map[string]bool{}
+	for _, review := range reviews {
+		fingerprints := markers(review.Body)
+		if len(fingerprints) == 0 && !strings.HasPrefix(review.Body, "AI review by gitai") {
+			continue
+		}
+		for _, fingerprint := range fingerprints {
+			posted[fingerprint] = true
+		}
+
+		var comments []struct {
+			Body string `json:"body"`
+		}
+		if err := g.api.do(http.MethodGet, fmt.Sprintf("%s/reviews/%d/comments", prPath, review.ID), nil, &comments); err != nil {
+			return nil, fmt.Errorf("failed to list review comments on pull request #%d: %w", mr.Number, err)
+		}
+		for _, comment := range comments {
+			for _, fingerprint := range markers(comment.Body) {
+				posted[fingerprint] = true
+			}
+		}
+	}
+	return posted, nil
+}
+
+// giteaReview is a review submitted on a pull request
+type giteaReview struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+// reviews returns all reviews on the pull request at prPath, page by page
+func (g *Gitea) reviews(prPath string) ([]giteaReview, error) {
+	const limit = 50
+	var all []giteaReview
+	for page := 1; ; page++ {
+		var reviews []giteaReview
+		if err := g.api.do(http.MethodGet, fmt.Sprintf("%s/reviews?limit=%d&page=%d", prPath, limit, page), nil, &reviews); err != nil {
+			return nil, err
+		}
+		all = append(all, reviews...)
+		if len(reviews) < limit {
+			return all, nil
+		}
+	}
+}
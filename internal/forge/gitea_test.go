@@ -0,0 +1,138 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+func giteaPR(number int, head string) map[string]any {
+	return map[string]any{
+		"number":   number,
+		"title":    fmt.Sprintf("PR %d", number),
+		"body":     "Description",
+		"html_url": fmt.Sprintf("https://gitea.example.com/owner/repo/pulls/%d", number),
+		"head":     map[string]any{"ref": head, "sha": "head123"},
+		"base":     map[string]any{"ref": "main", "sha": "base123"},
+	}
+}
+
+func TestGiteaMergeRequests(t *testing.T) {
+	api := newFakeAPI(t)
+	const pulls = "/api/v1/repos/owner/repo/pulls"
+	api.handle(http.MethodGet, pulls, func(w http.ResponseWriter, r *http.Request) {
+		// The first page is full, so the branch must be looked up on the second
+		var page []any
+		if r.URL.Query().Get("page") == "1" {
+			for i := 0; i < 50; i++ {
+				page = append(page, giteaPR(100+i, fmt.Sprintf("other-%d", i)))
+			}
+		} else {
+			page = append(page, giteaPR(7, "feature"))
+		}
+		writeJSON(w, http.StatusOK, page)
+	})
+	api.reply(http.MethodPatch, pulls+"/7", http.StatusOK, giteaPR(7, "feature"))
+	api.reply(http.MethodPost, pulls, http.StatusCreated, giteaPR(8, "feature"))
+
+	g, err := NewGitea(api.URL(), "secret", "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr, err := g.FindMergeRequest("feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr == nil || mr.Number != 7 || mr.SourceBranch != "feature" || mr.BaseSHA != "base123" || mr.HeadSHA != "head123" {
+		t.Fatalf("FindMergeRequest() = %+v", mr)
+	}
+	if auth := api.sent(http.MethodGet, pulls)[0].Header.Get("Authorization"); auth != "token secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	if mr, err := g.FindMergeRequest("missing"); err != nil || mr != nil {
+		t.Errorf("FindMergeRequest(missing) = %+v, %v, want nil", mr, err)
+	}
+
+	if _, err := g.UpdateMergeRequest(mr, "New title", ""); err != nil {
+		t.Fatal(err)
+	}
+	update := api.sent(http.MethodPatch, pulls+"/7")[0].Body
+	if _, ok := update["body"]; ok || update["title"] != "New title" {
+		t.Errorf("UpdateMergeRequest() sent %v, want only the title", update)
+	}
+
+	if _, err := g.CreateMergeRequest("feature", "main", "Title", "Body"); err != nil {
+		t.Fatal(err)
+	}
+	create := api.sent(http.MethodPost, pulls)[0].Body
+	if create["head"] != "feature" || create["base"] != "main" || create["body"] != "Body" {
+		t.Errorf("CreateMergeRequest() sent %v", create)
+	}
+}
+
+func TestGiteaPostReview(t *testing.T) {
+	api := newFakeAPI(t)
+	const pr = "/api/v1/repos/owner/repo/pulls/7"
+	api.handle(http.MethodGet, pr+"/reviews", func(w http.ResponseWriter, r *http.Request) {
+		// A full first page of foreign reviews; gitai's review is on the second
+		var page []any
+		if r.URL.Query().Get("page") != "2" {
+			for i := 0; i < 50; i++ {
+				page = append(page, map[string]any{"id": 100 + i, "body": "LGTM"})
+			}
+		} else {
+			page = append(page, map[string]any{"id": 1, "body": "AI review by gitai"}, map[string]any{"id": 2, "body": "LGTM"})
+		}
+		writeJSON(w, http.StatusOK, page)
+	})
+	api.reply(http.MethodGet, pr+"/reviews/1/comments", http.StatusOK, []any{
+		map[string]any{"body": "finding\n\n" + marker("aaaa")},
+	})
+	api.reply(http.MethodPost, pr+"/reviews", http.StatusOK, map[string]any{})
+
+	g, err := NewGitea(api.URL(), "secret", "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []Finding{
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 1, Comment: "posted"}, Fingerprint: "aaaa", Position: &git.LinePosition{NewLine: 1}},
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 2, Comment: "anchored"}, Fingerprint: "bbbb", Position: &git.LinePosition{NewLine: 2}},
+		{ReviewComment: models.ReviewComment{File: "b.go", Line: 9, Comment: "outside the diff"}, Fingerprint: "cccc"},
+	}
+	result, err := g.PostReview(&MergeRequest{Number: 7}, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (ReviewResult{Posted: 2, Duplicates: 1}) {
+		t.Errorf("PostReview() = %+v", *result)
+	}
+
+	// Only reviews gitai submitted are searched for markers
+	if len(api.sent(http.MethodGet, pr+"/reviews/2/comments")) != 0 {
+		t.Error("comments of a foreign review were fetched")
+	}
+
+	review := api.sent(http.MethodPost, pr+"/reviews")[0].Body
+	comments, _ := review["comments"].([]any)
+	if len(comments) != 1 || comments[0].(map[string]any)["new_position"] != float64(2) {
+		t.Errorf("review comments = %v", comments)
+	}
+	if body, _ := review["body"].(string); !strings.Contains(body, "`b.go:9`") || !strings.Contains(body, marker("cccc")) {
+		t.Errorf("review body = %q, want the finding outside the diff", body)
+	}
+
+	// Nothing new: no review is submitted
+	if _, err := g.PostReview(&MergeRequest{Number: 7}, findings[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(api.sent(http.MethodPost, pr+"/reviews")); n != 1 {
+		t.Errorf("submitted %d reviews, want 1", n)
+	}
+}
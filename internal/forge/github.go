@@ -0,0 +1,340 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitHub talks to the GitHub REST API for a single repository
+type GitHub struct {
+	api        *apiClient
+	graphqlAPI *apiClient
+	owner      string
+	repo       string
+}
+
+// NewGitHub creates a GitHub client for repository (e.g. "owner/repo") using
+// the REST API at apiURL (e.g. "https://api.github.com" or
+// "https://github.example.com/api/v3" for GitHub Enterprise)
+func NewGitHub(apiURL, token, repository string) (*GitHub, error) {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("invalid GitHub repository %q, expected owner/repo", repository)
+	}
+
+	headers := map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer " + token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	return &GitHub{
+		api:        newAPIClient(apiURL, headers),
+		graphqlAPI: newAPIClient(graphqlURL(apiURL), headers),
+		owner:      owner,
+		repo:       repo,
+	}, nil
+}
+
+// GitHubAPIURL returns the REST API URL for a GitHub instance, e.g.
+// "https://api.github.com" for "https://github.com"
+func GitHubAPIURL(baseURL string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() == "github.com" {
+		return "https://api.github.com"
+	}
+	return baseURL + "/api/v3"
+}
+
+// graphqlURL returns the GraphQL endpoint belonging to a REST API URL
+func graphqlURL(apiURL string) string {
+	if strings.HasSuffix(apiURL, "/api/v3") {
+		return strings.TrimSuffix(apiURL, "/v3") + "/graphql"
+	}
+	return apiURL + "/graphql"
+}
+
+type githubPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"base"`
+}
+
+func (p githubPullRequest) toMergeRequest() *MergeRequest {
+	return &MergeRequest{
+		Number:       p.Number,
+		Title:        p.Title,
+		Description:  p.Body,
+		URL:          p.HTMLURL,
+		SourceBranch: p.Head.Ref,
+		TargetBranch: p.Base.Ref,
+		BaseSHA:      p.Base.SHA,
+		HeadSHA:      p.Head.SHA,
+	}
+}
+
+func (g *GitHub) repoPath() string {
+	return "/repos/" + url.PathEscape(g.owner) + "/" + url.PathEscape(g.repo)
+}
+
+// FindMergeRequest returns the open pull request for branch, or nil if there is none
+func (g *GitHub) FindMergeRequest(branch string) (*MergeRequest, error) {
+	query := url.Values{"head": {g.owner + ":" + branch}, "state": {"open"}}
+	var prs []githubPullRequest
+	if err := g.api.do(http.MethodGet, g.repoPath()+"/pulls?"+query.Encode(), nil, &prs); err != nil {
+		return nil, fmt.Errorf("failed to find pull request for %s: %w", branch, err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0].toMergeRequest(), nil
+}
+
+// UpdateMergeRequest sets the title and, if non-empty, the body of the pull request
+func (g *GitHub) UpdateMergeRequest(mr *MergeRequest, title, description string) (*MergeRequest, error) {
+	body := map[string]string{"title": title}
+	if description != "" {
+		body["body"] = description
+	}
+
+	var updated githubPullRequest
+	if err := g.api.do(http.MethodPatch, fmt.Sprintf("%s/pulls/%d", g.repoPath(), mr.Number), body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update pull request #%d: %w", mr.Number, err)
+	}
+	return updated.toMergeRequest(), nil
+}
+
+// CreateMergeRequest opens a pull request from source into target
+func (g *GitHub) CreateMergeRequest(source, target, title, description string) (*MergeRequest, error) {
+	body := map[string]string{
+		"head":  source,
+		"base":  target,
+		"title": title,
+		"body":  description,
+	}
+
+	var created githubPullRequest
+	if err := g.api.do(http.MethodPost, g.repoPath()+"/pulls", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return created.toMergeRequest(), nil
+}
+
+// DefaultBranch returns the repository's default branch
+func (g *GitHub) DefaultBranch() (string, error) {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := g.api.do(http.MethodGet, g.repoPath(), nil, &repo); err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	return repo.DefaultBranch, nil
+}
+
+type githubReviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+type githubReviewThread struct {
+	ID         string `json:"id"`
+	IsResolved bool   `json:"isResolved"`
+	Comments   struct {
+		Nodes []struct {
+			Body string `json:"body"`
+		} `json:"nodes"`
+	} `json:"comments"`
+}
+
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        nodes { id isResolved comments(first: 1) { nodes { body } } }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+const resolveThreadMutation = `mutation($id: ID!) {
+  resolveReviewThread(input: {threadId: $id}) { thread { id } }
+}`
+
+// PostReview submits new findings as a single pull request review. Findings
+// anchored in the diff become line comments on the new side; the rest are
+// listed in the review body. Review threads gitai opened for findings that
+// are no longer reported are resolved, and a line comment whose thread was
+// resolved is posted again when its finding is reported again. Review
+// bodies cannot be resolved, so a finding listed in one is never posted
+// again.
+func (g *GitHub) PostReview(mr *MergeRequest, findings []Finding) (*ReviewResult, error) {
+	threads, err := g.reviewThreads(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	posted := map[string]bool{}
+	for _, thread := range threads {
+		if thread.IsResolved {
+			continue
+		}
+		for _, comment := range thread.Comments.Nodes {
+			for _, fingerprint := range markers(comment.Body) {
+				posted[fingerprint] = true
+			}
+		}
+	}
+
+	// Findings listed in review bodies have no thread to resolve
+	bodies, err := g.reviewBodies(mr)
+	if err != nil {
+		return nil, err
+	}
+	for _, body := range bodies {
+		for _, fingerprint := range markers(body) {
+			posted[fingerprint] = true
+		}
+	}
+
+	result := &ReviewResult{}
+	var body strings.Builder
+	body.WriteString("AI review by gitai")
+	comments := []githubReviewComment{}
+	for _, finding := range findings {
+		if posted[finding.Fingerprint] {
+			result.Duplicates++
+			continue
+		}
+		posted[finding.Fingerprint] = true
+		result.Posted++
+
+		if finding.Position == nil {
+			fmt.Fprintf(&body, "\n\n%s", findingBody(finding))
+			continue
+		}
+		comments = append(comments, githubReviewComment{
+			Path: finding.File,
+			Line: finding.Position.NewLine,
+			Side: "RIGHT",
+			Body: findingBody(finding),
+		})
+	}
+
+	if result.Posted > 0 {
+		payload := map[string]any{
+			"event":    "COMMENT",
+			"body":     body.String(),
+			"comments": comments,
+		}
+		// Without it GitHub anchors the comments to the latest commit
+		if mr.HeadSHA != "" {
+			payload["commit_id"] = mr.HeadSHA
+		}
+		if err := g.api.do(http.MethodPost, fmt.Sprintf("%s/pulls/%d/reviews", g.repoPath(), mr.Number), payload, nil); err != nil {
+			return nil, fmt.Errorf("failed to submit review on pull request #%d: %w", mr.Number, err)
+		}
+	}
+
+	current := fingerprints(findings)
+	for _, thread := range threads {
+		if thread.IsResolved || len(thread.Comments.Nodes) == 0 {
+			continue
+		}
+		stale := markers(thread.Comments.Nodes[0].Body)
+		if len(stale) == 0 || current[stale[0]] {
+			continue
+		}
+		if err := g.graphql(resolveThreadMutation, map[string]any{"id": thread.ID}, nil); err != nil {
+			return nil, fmt.Errorf("failed to resolve review thread: %w", err)
+		}
+		result.Resolved++
+	}
+
+	return result, nil
+}
+
+// reviewThreads returns every review thread of the pull request
+func (g *GitHub) reviewThreads(mr *MergeRequest) ([]githubReviewThread, error) {
+	var threads []githubReviewThread
+	variables := map[string]any{"owner": g.owner, "repo": g.repo, "number": mr.Number, "cursor": nil}
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						Nodes    []githubReviewThread `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		}
+		if err := g.graphql(reviewThreadsQuery, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to list review threads on pull request #%d: %w", mr.Number, err)
+		}
+		page := data.Repository.PullRequest.ReviewThreads
+		threads = append(threads, page.Nodes...)
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+}
+
+// reviewBodies returns the bodies of every review on the pull request
+func (g *GitHub) reviewBodies(mr *MergeRequest) ([]string, error) {
+	var bodies []string
+	path := fmt.Sprintf("%s/pulls/%d/reviews?per_page=100", g.repoPath(), mr.Number)
+	for path != "" {
+		var reviews []struct {
+			Body string `json:"body"`
+		}
+		header, err := g.api.request(http.MethodGet, path, nil, &reviews)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reviews on pull request #%d: %w", mr.Number, err)
+		}
+		for _, review := range reviews {
+			bodies = append(bodies, review.Body)
+		}
+		path = nextLink(header)
+	}
+	return bodies, nil
+}
+
+// graphql runs a GraphQL query and decodes its data into out
+func (g *GitHub) graphql(query string, variables map[string]any, out any) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	payload := map[string]any{"query": query, "variables": variables}
+	if err := g.graphqlAPI.do(http.MethodPost, "", payload, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("GraphQL error: %s", resp.Errors[0].Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, out)
+}
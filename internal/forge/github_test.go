@@ -0,0 +1,207 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+func TestGitHubAPIURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com":             "https://api.github.com",
+		"https://github.com/":            "https://api.github.com",
+		"https://github.example.com":     "https://github.example.com/api/v3",
+		"https://github.example.com/ghe": "https://github.example.com/ghe/api/v3",
+	}
+	for baseURL, want := range tests {
+		if got := GitHubAPIURL(baseURL); got != want {
+			t.Errorf("GitHubAPIURL(%q) = %q, want %q", baseURL, got, want)
+		}
+	}
+
+	if got := graphqlURL("https://github.example.com/api/v3"); got != "https://github.example.com/api/graphql" {
+		t.Errorf("graphqlURL() = %q", got)
+	}
+}
+
+func githubPR(number int) map[string]any {
+	return map[string]any{
+		"number":   number,
+		"title":    "Title",
+		"body":     "Description",
+		"html_url": "https://github.com/owner/repo/pull/7",
+		"head":     map[string]any{"ref": "feature", "sha": "head123"},
+		"base":     map[string]any{"ref": "main", "sha": "base123"},
+	}
+}
+
+func TestGitHubMergeRequests(t *testing.T) {
+	api := newFakeAPI(t)
+	const pulls = "/repos/owner/repo/pulls"
+	api.reply(http.MethodGet, pulls, http.StatusOK, []any{githubPR(7)})
+	api.reply(http.MethodPatch, pulls+"/7", http.StatusOK, githubPR(7))
+	api.reply(http.MethodPost, pulls, http.StatusCreated, githubPR(8))
+
+	g, err := NewGitHub(api.URL(), "secret", "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr, err := g.FindMergeRequest("feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MergeRequest{
+		Number: 7, Title: "Title", Description: "Description", URL: "https://github.com/owner/repo/pull/7",
+		SourceBranch: "feature", TargetBranch: "main", BaseSHA: "base123", HeadSHA: "head123",
+	}
+	if *mr != want {
+		t.Errorf("FindMergeRequest() = %+v, want %+v", *mr, want)
+	}
+	find := api.sent(http.MethodGet, pulls)[0]
+	if find.Query.Get("head") != "owner:feature" || find.Query.Get("state") != "open" {
+		t.Errorf("FindMergeRequest() query = %v", find.Query)
+	}
+	if find.Header.Get("Authorization") != "Bearer secret" || find.Header.Get("Accept") != "application/vnd.github+json" {
+		t.Errorf("headers = %v", find.Header)
+	}
+
+	if _, err := g.UpdateMergeRequest(mr, "New title", "New body"); err != nil {
+		t.Fatal(err)
+	}
+	update := api.sent(http.MethodPatch, pulls+"/7")[0].Body
+	if update["title"] != "New title" || update["body"] != "New body" {
+		t.Errorf("UpdateMergeRequest() sent %v", update)
+	}
+
+	if _, err := g.CreateMergeRequest("feature", "main", "Title", "Body"); err != nil {
+		t.Fatal(err)
+	}
+	create := api.sent(http.MethodPost, pulls)[0].Body
+	if create["head"] != "feature" || create["base"] != "main" {
+		t.Errorf("CreateMergeRequest() sent %v", create)
+	}
+
+	if _, err := NewGitHub(api.URL(), "secret", "owner"); err == nil {
+		t.Error("NewGitHub() without a repository name succeeded")
+	}
+}
+
+// githubThread returns a review thread whose first comment carries the
+// marker for fingerprint
+func githubThread(id, fingerprint string, resolved bool) map[string]any {
+	return map[string]any{
+		"id":         id,
+		"isResolved": resolved,
+		"comments":   map[string]any{"nodes": []any{map[string]any{"body": "finding\n\n" + marker(fingerprint)}}},
+	}
+}
+
+func TestGitHubPostReview(t *testing.T) {
+	api := newFakeAPI(t)
+	const pr = "/repos/owner/repo/pulls/7"
+
+	var resolved []string
+	api.handle(http.MethodPost, "/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if strings.Contains(req.Query, "resolveReviewThread") {
+			resolved = append(resolved, req.Variables["id"].(string))
+			writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{}})
+			return
+		}
+
+		// Two pages of threads, linked by the cursor
+		nodes := []any{githubThread("t1", "aaaa", false), githubThread("t2", "bbbb", true)}
+		pageInfo := map[string]any{"hasNextPage": true, "endCursor": "cursor1"}
+		if req.Variables["cursor"] == "cursor1" {
+			nodes = []any{githubThread("t3", "cccc", false)}
+			pageInfo = map[string]any{"hasNextPage": false, "endCursor": "cursor2"}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+			"repository": map[string]any{"pullRequest": map[string]any{"reviewThreads": map[string]any{
+				"nodes": nodes, "pageInfo": pageInfo,
+			}}},
+		}})
+	})
+
+	// Two pages of reviews, linked by the Link header
+	api.handle(http.MethodGet, pr+"/reviews", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(w, http.StatusOK, []any{map[string]any{"body": "AI review by gitai\n\n" + marker("eeee")}})
+			return
+		}
+		w.Header().Set("Link", `<`+api.URL()+pr+`/reviews?per_page=100&page=2>; rel="next"`)
+		writeJSON(w, http.StatusOK, []any{map[string]any{"body": "LGTM"}})
+	})
+	api.reply(http.MethodPost, pr+"/reviews", http.StatusOK, map[string]any{})
+
+	g, err := NewGitHub(api.URL(), "secret", "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []Finding{
+		// Open thread: a duplicate
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 1, Comment: "open"}, Fingerprint: "aaaa"},
+		// Resolved thread: posted again
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 2, Comment: "reopened"}, Fingerprint: "bbbb", Position: &git.LinePosition{NewLine: 2}},
+		// Listed in the body of a review on the second page: a duplicate
+		{ReviewComment: models.ReviewComment{File: "b.go", Line: 5, Comment: "in a review body"}, Fingerprint: "eeee"},
+		// New and outside the diff
+		{ReviewComment: models.ReviewComment{File: "c.go", Line: 9, Comment: "new"}, Fingerprint: "dddd"},
+	}
+	result, err := g.PostReview(&MergeRequest{Number: 7, HeadSHA: "head123"}, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (ReviewResult{Posted: 2, Duplicates: 2, Resolved: 1}) {
+		t.Errorf("PostReview() = %+v", *result)
+	}
+
+	// t3 on the second page is stale; the resolved t2 is left alone
+	if len(resolved) != 1 || resolved[0] != "t3" {
+		t.Errorf("resolved threads %v, want [t3]", resolved)
+	}
+
+	submitted := api.sent(http.MethodPost, pr+"/reviews")
+	if len(submitted) != 1 {
+		t.Fatalf("submitted %d reviews, want 1", len(submitted))
+	}
+	review := submitted[0].Body
+	comments, _ := review["comments"].([]any)
+	if len(comments) != 1 {
+		t.Fatalf("review comments = %v", comments)
+	}
+	comment := comments[0].(map[string]any)
+	if comment["path"] != "a.go" || comment["line"] != float64(2) || comment["side"] != "RIGHT" {
+		t.Errorf("review comment = %v", comment)
+	}
+	if body, _ := review["body"].(string); review["event"] != "COMMENT" || review["commit_id"] != "head123" || !strings.Contains(body, "`c.go:9`") {
+		t.Errorf("review = %v", review)
+	}
+}
+
+func TestGitHubGraphQLErrors(t *testing.T) {
+	api := newFakeAPI(t)
+	api.reply(http.MethodPost, "/graphql", http.StatusOK, map[string]any{
+		"errors": []any{map[string]any{"message": "Could not resolve to a PullRequest"}},
+	})
+
+	g, err := NewGitHub(api.URL(), "secret", "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.PostReview(&MergeRequest{Number: 7}, nil)
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve to a PullRequest") {
+		t.Errorf("PostReview() = %v, want the GraphQL error", err)
+	}
+}
@@ -0,0 +1,244 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLab talks to the GitLab REST API for a single project
+type GitLab struct {
+	api     *apiClient
+	project string
+}
+
+// NewGitLab creates a GitLab client for project (e.g. "group/project") on the
+// instance at baseURL (e.g. "https://gitlab.com")
+func NewGitLab(baseURL, token, project string) *GitLab {
+	return &GitLab{
+		api:     newAPIClient(strings.TrimSuffix(baseURL, "/")+"/api/v4", map[string]string{"PRIVATE-TOKEN": token}),
+		project: project,
+	}
+}
+
+type gitlabMergeRequest struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	WebURL       string `json:"web_url"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	DiffRefs     struct {
+		BaseSHA string `json:"base_sha"`
+		HeadSHA string `json:"head_sha"`
+	} `json:"diff_refs"`
+}
+
+func (m gitlabMergeRequest) toMergeRequest() *MergeRequest {
+	return &MergeRequest{
+		Number:       m.IID,
+		Title:        m.Title,
+		Description:  m.Description,
+		URL:          m.WebURL,
+		SourceBranch: m.SourceBranch,
+		TargetBranch: m.TargetBranch,
+		BaseSHA:      m.DiffRefs.BaseSHA,
+		HeadSHA:      m.DiffRefs.HeadSHA,
+	}
+}
+
+// projectPath returns the API path of the project, e.g. /projects/group%2Fproject
+func (g *GitLab) projectPath() string {
+	return "/projects/" + url.PathEscape(g.project)
+}
+
+// FindMergeRequest returns the open merge request for branch, or nil if there is none
+func (g *GitLab) FindMergeRequest(branch string) (*MergeRequest, error) {
+	query := url.Values{"source_branch": {branch}, "state": {"opened"}}
+	var mrs []gitlabMergeRequest
+	if err := g.api.do(http.MethodGet, g.projectPath()+"/merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+		return nil, fmt.Errorf("failed to find merge request for %s: %w", branch, err)
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+
+	// Only the single merge request endpoint reports the diff refs
+	var mr gitlabMergeRequest
+	if err := g.api.do(http.MethodGet, fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), mrs[0].IID), nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request !%d: %w", mrs[0].IID, err)
+	}
+	return mr.toMergeRequest(), nil
+}
+
+// UpdateMergeRequest sets the title and, if non-empty, the description of mr
+func (g *GitLab) UpdateMergeRequest(mr *MergeRequest, title, description string) (*MergeRequest, error) {
+	body := map[string]string{"title": title}
+	if description != "" {
+		body["description"] = description
+	}
+
+	var updated gitlabMergeRequest
+	if err := g.api.do(http.MethodPut, fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), mr.Number), body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update merge request !%d: %w", mr.Number, err)
+	}
+	return updated.toMergeRequest(), nil
+}
+
+// CreateMergeRequest opens a merge request from source into target
+func (g *GitLab) CreateMergeRequest(source, target, title, description string) (*MergeRequest, error) {
+	body := map[string]string{
+		"source_branch": source,
+		"target_branch": target,
+		"title":         title,
+		"description":   description,
+	}
+
+	var created gitlabMergeRequest
+	if err := g.api.do(http.MethodPost, g.projectPath()+"/merge_requests", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+	return created.toMergeRequest(), nil
+}
+
+// DefaultBranch returns the project's default branch
+func (g *GitLab) DefaultBranch() (string, error) {
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := g.api.do(http.MethodGet, g.projectPath(), nil, &project); err != nil {
+		return "", fmt.Errorf("failed to get project: %w", err)
+	}
+	return project.DefaultBranch, nil
+}
+
+type gitlabVersion struct {
+	BaseCommitSHA  string `json:"base_commit_sha"`
+	StartCommitSHA string `json:"start_commit_sha"`
+	HeadCommitSHA  string `json:"head_commit_sha"`
+}
+
+type gitlabDiscussion struct {
+	ID    string `json:"id"`
+	Notes []struct {
+		Body       string `json:"body"`
+		Resolvable bool   `json:"resolvable"`
+		Resolved   bool   `json:"resolved"`
+	} `json:"notes"`
+}
+
+type gitlabPosition struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha"`
+	StartSHA     string `json:"start_sha"`
+	HeadSHA      string `json:"head_sha"`
+	OldPath      string `json:"old_path"`
+	NewPath      string `json:"new_path"`
+	OldLine      int    `json:"old_line,omitempty"`
+	NewLine      int    `json:"new_line,omitempty"`
+}
+
+// PostReview opens a discussion for every finding gitai has no open
+// discussion for, positioned on the diff line where possible, and resolves
+// the discussions gitai opened for findings that are no longer reported
+func (g *GitLab) PostReview(mr *MergeRequest, findings []Finding) (*ReviewResult, error) {
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), mr.Number)
+
+	var versions []gitlabVersion
+	if err := g.api.do(http.MethodGet, mrPath+"/versions", nil, &versions); err != nil {
+		return nil, fmt.Errorf("failed to get diff versions of merge request !%d: %w", mr.Number, err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("merge request !%d has no diff", mr.Number)
+	}
+	version := versions[0]
+
+	discussions, err := g.discussions(mrPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discussions of merge request !%d: %w", mr.Number, err)
+	}
+
+	posted := map[string]bool{}
+	for _, discussion := range discussions {
+		// A finding whose discussion was resolved is posted again
+		if len(discussion.Notes) > 0 && discussion.Notes[0].Resolved {
+			continue
+		}
+		for _, note := range discussion.Notes {
+			for _, fingerprint := range markers(note.Body) {
+				posted[fingerprint] = true
+			}
+		}
+	}
+
+	result := &ReviewResult{}
+	for _, finding := range findings {
+		if posted[finding.Fingerprint] {
+			result.Duplicates++
+			continue
+		}
+		posted[finding.Fingerprint] = true
+
+		body := map[string]any{"body": findingBody(finding)}
+		if finding.Position != nil {
+			body["position"] = gitlabPosition{
+				PositionType: "text",
+				BaseSHA:      version.BaseCommitSHA,
+				StartSHA:     version.StartCommitSHA,
+				HeadSHA:      version.HeadCommitSHA,
+				OldPath:      finding.OldPath,
+				NewPath:      finding.File,
+				OldLine:      finding.Position.OldLine,
+				NewLine:      finding.Position.NewLine,
+			}
+		}
+
+		err := g.api.do(http.MethodPost, mrPath+"/discussions", body, nil)
+		if finding.Position != nil && hasStatus(err, http.StatusBadRequest) {
+			// GitLab rejects positions outside its own view of the diff
+			// with 400 Bad Request; fall back to a general discussion
+			// naming the location
+			finding.Position = nil
+			err = g.api.do(http.MethodPost, mrPath+"/discussions", map[string]any{"body": findingBody(finding)}, nil)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to post finding on merge request !%d: %w", mr.Number, err)
+		}
+		result.Posted++
+	}
+
+	current := fingerprints(findings)
+	for _, discussion := range discussions {
+		if len(discussion.Notes) == 0 {
+			continue
+		}
+		first := discussion.Notes[0]
+		stale := markers(first.Body)
+		if !first.Resolvable || first.Resolved || len(stale) == 0 || current[stale[0]] {
+			continue
+		}
+		if err := g.api.do(http.MethodPut, mrPath+"/discussions/"+url.PathEscape(discussion.ID)+"?resolved=true", nil, nil); err != nil {
+			return nil, fmt.Errorf("failed to resolve discussion on merge request !%d: %w", mr.Number, err)
+		}
+		result.Resolved++
+	}
+
+	return result, nil
+}
+
+// discussions returns every discussion of a merge request
+func (g *GitLab) discussions(mrPath string) ([]gitlabDiscussion, error) {
+	const perPage = 100
+	var all []gitlabDiscussion
+	for page := 1; ; page++ {
+		var discussions []gitlabDiscussion
+		if err := g.api.do(http.MethodGet, fmt.Sprintf("%s/discussions?per_page=%d&page=%d", mrPath, perPage, page), nil, &discussions); err != nil {
+			return nil, err
+		}
+		all = append(all, discussions...)
+		if len(discussions) < perPage {
+			return all, nil
+		}
+	}
+}
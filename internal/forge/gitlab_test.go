@@ -0,0 +1,217 @@
+package forge
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+const gitlabMR = "/api/v4/projects/group%2Fproject/merge_requests"
+
+func gitlabMergeRequestJSON(iid int, withRefs bool) map[string]any {
+	mr := map[string]any{
+		"iid":           iid,
+		"title":         "Title",
+		"description":   "Description",
+		"web_url":       "https://gitlab.example.com/group/project/-/merge_requests/7",
+		"source_branch": "feature",
+		"target_branch": "main",
+	}
+	if withRefs {
+		mr["diff_refs"] = map[string]string{"base_sha": "base123", "head_sha": "head123", "start_sha": "start123"}
+	}
+	return mr
+}
+
+func TestGitLabFindMergeRequest(t *testing.T) {
+	api := newFakeAPI(t)
+	api.reply(http.MethodGet, gitlabMR, http.StatusOK, []any{gitlabMergeRequestJSON(7, false)})
+	api.reply(http.MethodGet, gitlabMR+"/7", http.StatusOK, gitlabMergeRequestJSON(7, true))
+
+	g := NewGitLab(api.URL()+"/", "secret", "group/project")
+	mr, err := g.FindMergeRequest("feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MergeRequest{
+		Number: 7, Title: "Title", Description: "Description", URL: "https://gitlab.example.com/group/project/-/merge_requests/7",
+		SourceBranch: "feature", TargetBranch: "main", BaseSHA: "base123", HeadSHA: "head123",
+	}
+	if *mr != want {
+		t.Errorf("FindMergeRequest() = %+v, want %+v", *mr, want)
+	}
+
+	find := api.sent(http.MethodGet, gitlabMR)[0]
+	if find.Query.Get("source_branch") != "feature" || find.Query.Get("state") != "opened" {
+		t.Errorf("FindMergeRequest() query = %v", find.Query)
+	}
+	if find.Header.Get("PRIVATE-TOKEN") != "secret" {
+		t.Errorf("PRIVATE-TOKEN = %q", find.Header.Get("PRIVATE-TOKEN"))
+	}
+}
+
+func TestGitLabFindMergeRequestNone(t *testing.T) {
+	api := newFakeAPI(t)
+	api.reply(http.MethodGet, gitlabMR, http.StatusOK, []any{})
+
+	mr, err := NewGitLab(api.URL(), "secret", "group/project").FindMergeRequest("feature")
+	if err != nil || mr != nil {
+		t.Errorf("FindMergeRequest() = %+v, %v, want nil", mr, err)
+	}
+}
+
+func TestGitLabUpdateAndCreateMergeRequest(t *testing.T) {
+	api := newFakeAPI(t)
+	api.reply(http.MethodPut, gitlabMR+"/7", http.StatusOK, gitlabMergeRequestJSON(7, true))
+	api.reply(http.MethodPost, gitlabMR, http.StatusCreated, gitlabMergeRequestJSON(8, true))
+	g := NewGitLab(api.URL(), "secret", "group/project")
+
+	if _, err := g.UpdateMergeRequest(&MergeRequest{Number: 7}, "New title", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.UpdateMergeRequest(&MergeRequest{Number: 7}, "New title", "New description"); err != nil {
+		t.Fatal(err)
+	}
+	updates := api.sent(http.MethodPut, gitlabMR+"/7")
+	if _, ok := updates[0].Body["description"]; ok || updates[0].Body["title"] != "New title" {
+		t.Errorf("update without a description sent %v", updates[0].Body)
+	}
+	if updates[1].Body["description"] != "New description" {
+		t.Errorf("update with a description sent %v", updates[1].Body)
+	}
+
+	created, err := g.CreateMergeRequest("feature", "main", "Title", "Body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Number != 8 {
+		t.Errorf("CreateMergeRequest() = %+v", created)
+	}
+	create := api.sent(http.MethodPost, gitlabMR)[0].Body
+	if create["source_branch"] != "feature" || create["target_branch"] != "main" || create["description"] != "Body" {
+		t.Errorf("CreateMergeRequest() sent %v", create)
+	}
+}
+
+func TestGitLabCreateMergeRequestError(t *testing.T) {
+	api := newFakeAPI(t)
+	api.reply(http.MethodPost, gitlabMR, http.StatusConflict, map[string]any{"message": []string{"Another open merge request already exists"}})
+
+	_, err := NewGitLab(api.URL(), "secret", "group/project").CreateMergeRequest("feature", "main", "Title", "")
+	if err == nil || !strings.Contains(err.Error(), "409 Conflict") || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("CreateMergeRequest() = %v, want the status and message", err)
+	}
+}
+
+// gitlabReviewAPI serves a merge request with one open gitai discussion for
+// aaaa, a resolved one for bbbb, an open one for the stale finding cccc and
+// a discussion by someone else
+func gitlabReviewAPI(t *testing.T) *fakeAPI {
+	api := newFakeAPI(t)
+	discussion := func(id, body string, resolved bool) map[string]any {
+		return map[string]any{
+			"id":    id,
+			"notes": []any{map[string]any{"body": body, "resolvable": true, "resolved": resolved}},
+		}
+	}
+	api.reply(http.MethodGet, gitlabMR+"/7/versions", http.StatusOK, []any{
+		map[string]string{"base_commit_sha": "base123", "start_commit_sha": "start123", "head_commit_sha": "head123"},
+	})
+	api.reply(http.MethodGet, gitlabMR+"/7/discussions", http.StatusOK, []any{
+		discussion("d1", "open\n\n"+marker("aaaa"), false),
+		discussion("d2", "fixed\n\n"+marker("bbbb"), true),
+		discussion("d3", "stale\n\n"+marker("cccc"), false),
+		discussion("d4", "Looks good to me", false),
+	})
+	api.reply(http.MethodPut, gitlabMR+"/7/discussions/d1", http.StatusOK, map[string]any{})
+	api.reply(http.MethodPut, gitlabMR+"/7/discussions/d3", http.StatusOK, map[string]any{})
+	return api
+}
+
+func TestGitLabPostReview(t *testing.T) {
+	api := gitlabReviewAPI(t)
+	api.reply(http.MethodPost, gitlabMR+"/7/discussions", http.StatusCreated, map[string]any{})
+
+	findings := []Finding{
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 1, Comment: "open"}, Fingerprint: "aaaa"},
+		{ReviewComment: models.ReviewComment{File: "a.go", Line: 2, Comment: "reopened"}, Fingerprint: "bbbb"},
+		{ReviewComment: models.ReviewComment{File: "b.go", Line: 3, Comment: "new"}, OldPath: "old/b.go", Fingerprint: "dddd", Position: &git.LinePosition{OldLine: 2, NewLine: 3}},
+	}
+	result, err := NewGitLab(api.URL(), "secret", "group/project").PostReview(&MergeRequest{Number: 7}, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (ReviewResult{Posted: 2, Duplicates: 1, Resolved: 1}) {
+		t.Errorf("PostReview() = %+v", *result)
+	}
+
+	posted := api.sent(http.MethodPost, gitlabMR+"/7/discussions")
+	if len(posted) != 2 {
+		t.Fatalf("opened %d discussions, want 2", len(posted))
+	}
+	if body, _ := posted[0].Body["body"].(string); !strings.Contains(body, "`a.go:2`") || !strings.Contains(body, marker("bbbb")) {
+		t.Errorf("general discussion body = %q", body)
+	}
+	position, _ := posted[1].Body["position"].(map[string]any)
+	want := map[string]any{
+		"position_type": "text", "base_sha": "base123", "start_sha": "start123", "head_sha": "head123",
+		"old_path": "old/b.go", "new_path": "b.go", "old_line": float64(2), "new_line": float64(3),
+	}
+	for key, value := range want {
+		if position[key] != value {
+			t.Errorf("position[%s] = %v, want %v", key, position[key], value)
+		}
+	}
+
+	resolve := api.sent(http.MethodPut, gitlabMR+"/7/discussions/d3")
+	if len(resolve) != 1 || resolve[0].Query.Get("resolved") != "true" {
+		t.Errorf("stale discussion not resolved: %+v", resolve)
+	}
+}
+
+func TestGitLabPostReviewFallsBackOnInvalidPosition(t *testing.T) {
+	api := gitlabReviewAPI(t)
+	api.handle(http.MethodPost, gitlabMR+"/7/discussions", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(readBody(r), `"position"`) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "400 Bad request - Note {:line_code=>[\"must be a valid line code\"]}"})
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{})
+	})
+
+	findings := []Finding{
+		{ReviewComment: models.ReviewComment{File: "b.go", Line: 3, Comment: "new"}, Fingerprint: "dddd", Position: &git.LinePosition{NewLine: 3}},
+	}
+	result, err := NewGitLab(api.URL(), "secret", "group/project").PostReview(&MergeRequest{Number: 7}, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Posted != 1 {
+		t.Errorf("PostReview() = %+v, want one posted finding", *result)
+	}
+	posted := api.sent(http.MethodPost, gitlabMR+"/7/discussions")
+	if len(posted) != 2 || posted[1].Body["position"] != nil || !strings.Contains(posted[1].Body["body"].(string), "`b.go:3`") {
+		t.Errorf("fallback discussion = %+v", posted)
+	}
+}
+
+func TestGitLabPostReviewReturnsOtherErrors(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError} {
+		api := gitlabReviewAPI(t)
+		api.reply(http.MethodPost, gitlabMR+"/7/discussions", status, map[string]string{"message": http.StatusText(status)})
+
+		findings := []Finding{
+			{ReviewComment: models.ReviewComment{File: "b.go", Line: 3, Comment: "new"}, Fingerprint: "dddd", Position: &git.LinePosition{NewLine: 3}},
+		}
+		_, err := NewGitLab(api.URL(), "secret", "group/project").PostReview(&MergeRequest{Number: 7}, findings)
+		if !hasStatus(err, status) {
+			t.Errorf("PostReview() with status %d = %v, want that error", status, err)
+		}
+		if n := len(api.sent(http.MethodPost, gitlabMR+"/7/discussions")); n != 1 {
+			t.Errorf("status %d: sent %d discussions, want no fallback", status, n)
+		}
+	}
+}
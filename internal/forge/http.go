@@ -0,0 +1,122 @@
+package forge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// apiClient performs authenticated JSON requests against a forge REST API
+type apiClient struct {
+	baseURL string
+	headers map[string]string
+	http    *http.Client
+}
+
+func newAPIClient(baseURL string, headers map[string]string) *apiClient {
+	return &apiClient{
+		baseURL: baseURL,
+		headers: headers,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiError is returned for responses with a non-2xx status
+type apiError struct {
+	Method     string
+	Path       string
+	Status     string
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s returned %s: %s", e.Method, e.Path, e.Status, e.Body)
+}
+
+// hasStatus reports whether err is an API response with the given status code
+func hasStatus(err error, code int) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+// do sends body as JSON to path and decodes the JSON response into out.
+// Either body or out may be nil.
+func (c *apiClient) do(method, path string, body, out any) error {
+	_, err := c.request(method, path, body, out)
+	return err
+}
+
+// request works like do and also returns the response headers. path may be
+// an absolute URL, e.g. a pagination link returned by the API.
+func (c *apiClient) request(method, path string, body, out any) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := path
+	if !strings.Contains(path, "://") {
+		target = c.baseURL + path
+	}
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of %s %s: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{
+			Method:     method,
+			Path:       path,
+			Status:     resp.Status,
+			StatusCode: resp.StatusCode,
+			Body:       string(bytes.TrimSpace(data)),
+		}
+	}
+
+	if out == nil || len(data) == 0 {
+		return resp.Header, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return nil, fmt.Errorf("failed to parse response of %s %s: %w", method, path, err)
+	}
+	return resp.Header, nil
+}
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink returns the URL of the next page from a Link header, or "" on the
+// last page
+func nextLink(header http.Header) string {
+	if m := nextLinkPattern.FindStringSubmatch(header.Get("Link")); m != nil {
+		return m[1]
+	}
+	return ""
+}
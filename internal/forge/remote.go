@@ -0,0 +1,46 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote is a parsed git remote URL
+type Remote struct {
+	// Host is the hostname of the forge, without port
+	Host string
+	// Path is the repository path, e.g. "group/subgroup/project"
+	Path string
+}
+
+// ParseRemote parses HTTPS, SSH and scp-style git remote URLs
+func ParseRemote(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Remote{}, fmt.Errorf("empty remote URL")
+	}
+
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		at := strings.Index(raw, "@")
+		colon := strings.Index(raw, ":")
+		if colon < 0 || colon < at {
+			return Remote{}, fmt.Errorf("unsupported remote URL %q", raw)
+		}
+		host, path = raw[at+1:colon], raw[colon+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return Remote{}, fmt.Errorf("unsupported remote URL %q", raw)
+	}
+	return Remote{Host: host, Path: path}, nil
+}
@@ -0,0 +1,84 @@
+package forge
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/richardamare/gitai/internal/git"
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// diffContext is the number of unchanged lines forges show around a change
+const diffContext = 3
+
+// Finding is a review comment located in the merge request diff
+type Finding struct {
+	models.ReviewComment
+	// OldPath is the path of the file before the change
+	OldPath string
+	// Position anchors the finding in the diff; nil if its line is not part of the diff
+	Position *git.LinePosition
+	// Fingerprint identifies the finding uniquely within its review
+	Fingerprint string
+}
+
+// ReviewResult summarises what posting a review changed on the forge
+type ReviewResult struct {
+	Posted     int
+	Duplicates int
+	Resolved   int
+}
+
+// LocateFindings anchors review findings to the lines of diff they refer to
+func LocateFindings(review *models.MrReviewDetails, diff string) []Finding {
+	files := map[string]git.FileDiff{}
+	for _, file := range git.SplitDiff(diff) {
+		files[file.Path()] = file
+	}
+
+	fingerprints := review.Fingerprints()
+	findings := make([]Finding, 0, len(review.Review))
+	for i, comment := range review.Review {
+		finding := Finding{ReviewComment: comment, OldPath: comment.File, Fingerprint: fingerprints[i]}
+		if file, ok := files[comment.File]; ok {
+			if file.OldPath != "/dev/null" {
+				finding.OldPath = file.OldPath
+			}
+			if position, ok := file.Position(comment.Line, diffContext); ok {
+				finding.Position = &position
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+var markerPattern = regexp.MustCompile(`<!-- gitai:finding ([0-9a-f]+) -->`)
+
+// findingBody renders a finding as a comment body carrying a hidden marker,
+// so later runs can recognise the comments gitai posted
+func findingBody(finding Finding) string {
+	body := FormatComment(finding.ReviewComment)
+	if finding.Position == nil {
+		body = fmt.Sprintf("`%s:%d` %s", finding.File, finding.Line, body)
+	}
+	return fmt.Sprintf("%s\n\n<!-- gitai:finding %s -->", body, finding.Fingerprint)
+}
+
+// markers returns the finding fingerprints embedded in a comment body
+func markers(body string) []string {
+	var fingerprints []string
+	for _, m := range markerPattern.FindAllStringSubmatch(body, -1) {
+		fingerprints = append(fingerprints, m[1])
+	}
+	return fingerprints
+}
+
+// fingerprints returns the set of fingerprints of findings
+func fingerprints(findings []Finding) map[string]bool {
+	set := map[string]bool{}
+	for _, finding := range findings {
+		set[finding.Fingerprint] = true
+	}
+	return set
+}
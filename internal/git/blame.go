@@ -0,0 +1,74 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Blame returns the commit that last changed each line of path at rev,
+// indexed by line number minus one
+func (c *Client) Blame(rev, path string) ([]string, error) {
+	cmd := exec.Command("git", "blame", "--porcelain", rev, "--", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", path, err)
+	}
+
+	lines, err := parseBlame(string(output))
+	if err != nil {
+		return nil, err
+	}
+	var commits []string
+	for _, line := range lines {
+		for len(commits) < line.Number {
+			commits = append(commits, "")
+		}
+		commits[line.Number-1] = line.Commit
+	}
+	return commits, nil
+}
+
+// parseBlame reads the output of git blame --porcelain. Header lines start
+// with the commit; the line's content follows a tab, so it is never taken
+// for a header.
+func parseBlame(output string) ([]BlameLine, error) {
+	var lines []BlameLine
+	var current BlameLine
+	paths := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if text, ok := strings.CutPrefix(line, "\t"); ok {
+			current.Text = text
+			current.Path = paths[current.Commit]
+			lines = append(lines, current)
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "filename "); ok {
+			paths[current.Commit] = name
+			continue
+		}
+		// Each header starts with "<sha> <orig line> <final line> [<group size>]"
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields[0]) < 40 || !isHex(fields[0]) {
+			continue
+		}
+		if final, err := strconv.Atoi(fields[2]); err == nil {
+			current = BlameLine{Number: final, Commit: fields[0]}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
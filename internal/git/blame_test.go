@@ -0,0 +1,36 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBlameSkipsContentLines(t *testing.T) {
+	a := strings.Repeat("a", 40)
+	b := strings.Repeat("b", 40)
+	output := a + " 1 1 2\n" +
+		"author Test\n" +
+		"filename old.txt\n" +
+		"\tfirst line\n" +
+		a + " 2 2\n" +
+		// Content that looks like a header for line 1
+		"\t" + b + " 1 1 1\n" +
+		b + " 3 3 1\n" +
+		"author Test\n" +
+		"filename new.txt\n" +
+		"\tthird line\n"
+
+	lines, err := parseBlame(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []BlameLine{
+		{Number: 1, Text: "first line", Commit: a, Path: "old.txt"},
+		{Number: 2, Text: b + " 1 1 1", Commit: a, Path: "old.txt"},
+		{Number: 3, Text: "third line", Commit: b, Path: "new.txt"},
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("parseBlame() =\n%+v\nwant\n%+v", lines, want)
+	}
+}
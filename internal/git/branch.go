@@ -0,0 +1,26 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CheckBranchName returns an error if name is not a valid branch name
+func (c *Client) CheckBranchName(name string) error {
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%q is not a valid branch name: %w", name, err)
+	}
+	return nil
+}
+
+// CreateBranch creates a branch at HEAD and switches to it, keeping local
+// changes
+func (c *Client) CreateBranch(name string) error {
+	cmd := exec.Command("git", "switch", "--create", name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create branch %s: %s: %w", name, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 package git
 
 import (
+	"cmp"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 )
@@ -11,52 +13,57 @@ type Client struct{}
 
 // NewClient creates a new git client
 func NewClient() *Client {
-    return &Client{}
+	return &Client{}
 }
 
 // GetStagedDiff returns the staged diff with extended context
 func (c *Client) GetStagedDiff() (string, error) {
-    cmd := exec.Command("git", "diff", "--staged", "-U50")
-    output, err := cmd.Output()
-    if err != nil {
-        return "", fmt.Errorf("failed to get staged diff. Is git installed? %w", err)
-    }
-    return strings.TrimSpace(string(output)), nil
+	cmd := exec.Command("git", "diff", "--staged", "-U50")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged diff. Is git installed? %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // GetDiff returns the diff for specified files or all changes
 func (c *Client) GetDiff(files ...string) (string, error) {
-    args := []string{"diff"}
-    if len(files) > 0 {
-        args = append(args, files...)
-    }
-    
-    cmd := exec.Command("git", args...)
-    output, err := cmd.Output()
-    if err != nil {
-        return "", fmt.Errorf("failed to get diff: %w", err)
-    }
-    return strings.TrimSpace(string(output)), nil
+	args := []string{"diff"}
+	if len(files) > 0 {
+		args = append(args, files...)
+	}
+
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // Commit creates a commit with the given message
 func (c *Client) Commit(message string) error {
-    cmd := exec.Command("git", "commit", "-m", message)
-    if err := cmd.Run(); err != nil {
-        return fmt.Errorf("failed to commit: %w", err)
-    }
-    fmt.Println("✅ Successfully committed changes!")
-    return nil
+	cmd := exec.Command("git", "commit", "-m", message)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
 }
 
-// GetUnifiedDiff returns the diff of all changes (staged and unstaged) with extended context
+// GetUnifiedDiff returns the diff of all changes (staged and unstaged) to
+// tracked files with extended context. Before the first commit there is no
+// HEAD to compare with, so it returns the staged changes.
 func (c *Client) GetUnifiedDiff() (string, error) {
-    cmd := exec.Command("git", "diff", "-U50")
-    output, err := cmd.Output()
-    if err != nil {
-        return "", fmt.Errorf("failed to get unified diff. Is git installed? %w", err)
-    }
-    return strings.TrimSpace(string(output)), nil
+	args := []string{"diff", "HEAD", "-U50"}
+	if exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD").Run() != nil {
+		args = []string{"diff", "--cached", "-U50"}
+	}
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get unified diff. Is git installed? %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // GetDiffFromMain returns the diff between the current branch and the main branch
@@ -79,18 +86,127 @@ func (c *Client) GetBranchDiff() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// HasChanges reports whether tracked files have staged or unstaged changes
+func (c *Client) HasChanges() (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to get status of the working tree: %w", err)
+	}
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
 // GetCurrentBranch returns the current git branch
 func (c *Client) GetCurrentBranch() (string, error) {
-    cmd := exec.Command("git", "branch", "--show-current")
-    output, err := cmd.Output()
-    if err != nil {
-        return "", fmt.Errorf("failed to get current branch: %w", err)
-    }
-    return strings.TrimSpace(string(output)), nil
+	cmd := exec.Command("git", "branch", "--show-current")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetCommentChar returns the value of core.commentChar, which starts the
+// comment lines git adds to commit messages. It defaults to "#" and may be
+// "auto".
+func (c *Client) GetCommentChar() string {
+	cmd := exec.Command("git", "config", "--get", "core.commentChar")
+	output, err := cmd.Output()
+	if err != nil {
+		return "#"
+	}
+	return cmp.Or(strings.TrimSpace(string(output)), "#")
 }
 
 // IsGitRepo checks if current directory is a git repository
 func (c *Client) IsGitRepo() bool {
-    cmd := exec.Command("git", "rev-parse", "--git-dir")
-    return cmd.Run() == nil
-}
\ No newline at end of file
+	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	return cmd.Run() == nil
+}
+
+// GetRepoRoot returns the absolute path of the repository's top-level directory
+func (c *Client) GetRepoRoot() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRemoteURL returns the URL of the named remote
+func (c *Client) GetRemoteURL(remote string) (string, error) {
+	cmd := exec.Command("git", "remote", "get-url", remote)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get URL of remote %s: %w", remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetDiffBetween returns the changes head introduces since its merge base
+// with base, as shown for a merge request
+func (c *Client) GetDiffBetween(base, head string) (string, error) {
+	if head == "" {
+		head = "HEAD"
+	}
+	cmd := exec.Command("git", "diff", base+"..."+head, "-U50")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff between %s and %s. Are both commits fetched (shallow clones may miss them)? %w", base, head, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetRemoteHead returns the default branch of remote as a remote-tracking
+// ref, e.g. "origin/main"
+func (c *Client) GetRemoteHead(remote string) (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the default branch of %s. Run git remote set-head %s --auto: %w", remote, remote, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetMergeBase returns the best common ancestor of two commits
+func (c *Client) GetMergeBase(a, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", a, b)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the merge base of %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RebaseAutosquash rebases the current branch onto base, squashing
+// fixup! commits into their targets without opening an editor. Local
+// changes are stashed for the rebase and restored afterwards with staged
+// changes still staged, which git rebase --autostash does not do.
+func (c *Client) RebaseAutosquash(base string) error {
+	dirty, err := c.HasChanges()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		if output, err := exec.Command("git", "stash", "push", "--quiet", "--message", "gitai autosquash").CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to stash local changes: %s: %w", strings.TrimSpace(string(output)), err)
+		}
+	}
+
+	cmd := exec.Command("git", "rebase", "--interactive", "--autosquash", base)
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=true")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if dirty {
+			return fmt.Errorf("failed to rebase onto %s, your local changes are in the latest stash entry: %s: %w", base, strings.TrimSpace(string(output)), err)
+		}
+		return fmt.Errorf("failed to rebase onto %s: %s: %w", base, strings.TrimSpace(string(output)), err)
+	}
+
+	if dirty {
+		if output, err := exec.Command("git", "stash", "pop", "--index", "--quiet").CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to restore local changes from the latest stash entry: %s: %w", strings.TrimSpace(string(output)), err)
+		}
+	}
+	return nil
+}
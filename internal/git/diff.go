@@ -0,0 +1,288 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is the part of a unified diff that touches a single file
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Text    string
+	Hunks   []Hunk
+}
+
+// Hunk is a contiguous block of changes within a file diff
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Header is the "@@ -a,b +c,d @@" line
+	Header string
+	// Lines holds the hunk body, each line prefixed with ' ', '+', '-' or '\'
+	Lines []string
+}
+
+// LinePosition locates a line on both sides of a diff. A zero line means the
+// line does not exist on that side.
+type LinePosition struct {
+	OldLine int
+	NewLine int
+}
+
+// Path returns the path the change should be reported under
+func (f FileDiff) Path() string {
+	if f.NewPath == "" || f.NewPath == "/dev/null" {
+		return f.OldPath
+	}
+	return f.NewPath
+}
+
+// SplitDiff splits a unified git diff into one entry per file
+func SplitDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var current []string
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		files = append(files, newFileDiff(current))
+		current = nil
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+		}
+		if len(current) == 0 && !strings.HasPrefix(line, "diff --git ") {
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return files
+}
+
+// Position returns where a line of the new file appears in the diff. Only
+// added lines and unchanged lines within context lines of a change count as
+// part of the diff, matching what forges display with their default context.
+func (f FileDiff) Position(line, context int) (LinePosition, bool) {
+	for _, hunk := range f.Hunks {
+		if line < hunk.NewStart || line >= hunk.NewStart+hunk.NewLines {
+			continue
+		}
+
+		var changes []int
+		for i, l := range hunk.Lines {
+			if strings.HasPrefix(l, "+") || strings.HasPrefix(l, "-") {
+				changes = append(changes, i)
+			}
+		}
+
+		oldLine, newLine := hunk.OldStart, hunk.NewStart
+		for i, l := range hunk.Lines {
+			switch {
+			case strings.HasPrefix(l, "+"):
+				if newLine == line {
+					return LinePosition{NewLine: line}, true
+				}
+				newLine++
+			case strings.HasPrefix(l, "-"):
+				oldLine++
+			case strings.HasPrefix(l, "\\"):
+			default:
+				if newLine == line && nearChange(i, changes, context) {
+					return LinePosition{OldLine: oldLine, NewLine: line}, true
+				}
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return LinePosition{}, false
+}
+
+func nearChange(index int, changes []int, context int) bool {
+	for _, change := range changes {
+		if index-change <= context && change-index <= context {
+			return true
+		}
+	}
+	return false
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parseHunkHeader parses a "@@ -a,b +c,d @@" line
+func parseHunkHeader(line string) (Hunk, bool) {
+	m := hunkHeaderPattern.FindStringSubmatch(line)
+	if m == nil {
+		return Hunk{}, false
+	}
+	count := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	oldStart, _ := strconv.Atoi(m[1])
+	newStart, _ := strconv.Atoi(m[3])
+	return Hunk{
+		OldStart: oldStart,
+		OldLines: count(m[2]),
+		NewStart: newStart,
+		NewLines: count(m[4]),
+		Header:   line,
+	}, true
+}
+
+func newFileDiff(lines []string) FileDiff {
+	file := FileDiff{Text: strings.Join(lines, "\n")}
+	file.OldPath, file.NewPath = parseGitHeader(lines[0])
+
+	for i, line := range lines[1:] {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			file.Hunks = parseHunks(lines[1+i:])
+			return file
+		case strings.HasPrefix(line, "--- "):
+			file.OldPath = trimPathPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			file.NewPath = trimPathPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "rename from "):
+			file.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			file.NewPath = strings.TrimPrefix(line, "rename to ")
+		}
+	}
+	return file
+}
+
+// parseHunks parses the hunks of a file diff, starting at its first "@@" line
+func parseHunks(lines []string) []Hunk {
+	var hunks []Hunk
+	for _, line := range lines {
+		if hunk, ok := parseHunkHeader(line); ok {
+			hunks = append(hunks, hunk)
+			continue
+		}
+		if len(hunks) == 0 {
+			continue
+		}
+		current := &hunks[len(hunks)-1]
+		if line == "" {
+			// An empty context line whose leading space was trimmed, or
+			// the blank line separating files
+			if current.complete() {
+				continue
+			}
+			line = " "
+		}
+		current.Lines = append(current.Lines, line)
+	}
+	return hunks
+}
+
+// complete reports whether the hunk body covers all lines its header announces
+func (h Hunk) complete() bool {
+	oldLines, newLines := 0, 0
+	for _, l := range h.Lines {
+		switch {
+		case strings.HasPrefix(l, "+"):
+			newLines++
+		case strings.HasPrefix(l, "-"):
+			oldLines++
+		case strings.HasPrefix(l, "\\"):
+		default:
+			oldLines++
+			newLines++
+		}
+	}
+	return oldLines >= h.OldLines && newLines >= h.NewLines
+}
+
+// parseGitHeader extracts both paths from a "diff --git a/x b/y" line
+func parseGitHeader(line string) (string, string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	idx := strings.LastIndex(rest, " b/")
+	if idx < 0 {
+		return rest, rest
+	}
+	return strings.TrimPrefix(rest[:idx], "a/"), rest[idx+len(" b/"):]
+}
+
+func trimPathPrefix(path, prefix string) string {
+	path = strings.TrimSuffix(path, "\t")
+	if path == "/dev/null" {
+		return path
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// header returns the lines of the file diff before its first hunk
+func (f FileDiff) header() string {
+	header, _, _ := strings.Cut(f.Text, "\n@@")
+	return strings.TrimRight(header, "\n") + "\n"
+}
+
+// Atomic reports whether the file diff can only be applied as a whole, as
+// for added, deleted, renamed and binary files and mode changes
+func (f FileDiff) Atomic() bool {
+	if len(f.Hunks) == 0 {
+		return true
+	}
+	header := f.header()
+	for _, marker := range []string{"\nnew file mode", "\ndeleted file mode", "\nrename from", "\ncopy from", "\nold mode", "\nBinary files", "\nGIT binary patch"} {
+		if strings.Contains(header, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Patch returns a patch applying only the hunks with the given indexes
+func (f FileDiff) Patch(hunks []int) string {
+	var b strings.Builder
+	b.WriteString(f.header())
+	for _, i := range hunks {
+		b.WriteString(f.Hunks[i].Text())
+	}
+	return b.String()
+}
+
+// Text returns the hunk as it appears in a patch
+func (h Hunk) Text() string {
+	return h.Header + "\n" + strings.Join(h.Lines, "\n") + "\n"
+}
+
+// TruncateDiff shortens a diff to at most perFile lines of each file and
+// total lines overall. Left-out lines and files are noted in the result.
+func TruncateDiff(diff string, perFile, total int) string {
+	var b strings.Builder
+	var omitted []string
+	for _, file := range SplitDiff(diff) {
+		lines := strings.Split(file.Text, "\n")
+		if total <= 0 {
+			omitted = append(omitted, file.Path())
+			continue
+		}
+		limit := min(perFile, total)
+		if len(lines) > limit {
+			lines = append(lines[:limit:limit], fmt.Sprintf("[... %d more lines of %s left out]", len(lines)-limit, file.Path()))
+		}
+		total -= len(lines)
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n")
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(&b, "[... diffs of %d more files left out: %s]\n", len(omitted), strings.Join(omitted, ", "))
+	}
+	return strings.TrimSpace(b.String())
+}
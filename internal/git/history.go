@@ -0,0 +1,56 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// BlameLine is a line of a file and the commit that last changed it
+type BlameLine struct {
+	Number int
+	Text   string
+	Commit string
+	// Path is the file's name in Commit, which differs after renames
+	Path string
+}
+
+// BlameRange blames lines start to end of path at rev, following lines
+// moved or copied from other files
+func (c *Client) BlameRange(rev, path string, start, end int) ([]BlameLine, error) {
+	cmd := exec.Command("git", "blame", "--porcelain", "-M", "-C", "-L", fmt.Sprintf("%d,%d", start, end), rev, "--", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame lines %d-%d of %s: %w", start, end, path, err)
+	}
+	return parseBlame(string(output))
+}
+
+// LineChange is a commit in the history of a line range and the changes it
+// made to the range
+type LineChange struct {
+	Commit
+	Diff string
+}
+
+// GetLineHistory returns the commits that changed lines start to end of
+// path up to rev, newest first. git log -L follows the range across
+// renames.
+func (c *Client) GetLineHistory(rev, path string, start, end int) ([]LineChange, error) {
+	cmd := exec.Command("git", "log", "--no-color", fmt.Sprintf("-L%d,%d:%s", start, end, path),
+		"--format="+recordSep+commitFormat+fieldSep, rev)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the history of lines %d-%d of %s: %w", start, end, path, err)
+	}
+
+	var changes []LineChange
+	for _, record := range strings.Split(string(output), recordSep) {
+		fields := strings.SplitN(record, fieldSep, commitFields+1)
+		if len(fields) != commitFields+1 {
+			continue
+		}
+		changes = append(changes, LineChange{Commit: parseCommit(fields), Diff: strings.TrimSpace(fields[commitFields])})
+	}
+	return changes, nil
+}
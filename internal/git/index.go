@@ -0,0 +1,94 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GetStagedPatch returns the staged changes as a patch that can be applied
+// with ApplyToIndex, including binary files
+func (c *Client) GetStagedPatch() (string, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--binary", "--no-color", "--no-ext-diff")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged patch: %w", err)
+	}
+	return string(output), nil
+}
+
+// GetHead returns the hash of the current commit
+func (c *Client) GetHead() (string, error) {
+	return c.ResolveCommit("HEAD")
+}
+
+// WriteIndexTree stores the index as a tree object and returns its hash, so
+// the index can be restored with RestoreIndex
+func (c *Client) WriteIndexTree() (string, error) {
+	cmd := exec.Command("git", "write-tree")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to save the index: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RestoreIndex replaces the index with the tree saved by WriteIndexTree
+func (c *Client) RestoreIndex(tree string) error {
+	cmd := exec.Command("git", "read-tree", tree)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restore the index: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ResetIndex unstages all changes, keeping the working tree
+func (c *Client) ResetIndex() error {
+	cmd := exec.Command("git", "reset", "--quiet")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset the index: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ResetSoft moves the current branch to commit, keeping index and working tree
+func (c *Client) ResetSoft(commit string) error {
+	cmd := exec.Command("git", "reset", "--soft", "--quiet", commit)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to reset to %s: %s: %w", commit, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// ApplyToIndex applies patch to the index only
+func (c *Client) ApplyToIndex(patch string) error {
+	cmd := exec.Command("git", "apply", "--cached", "--whitespace=nowarn", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to apply patch to the index: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// CommitIndex commits the index on top of HEAD with message. Unlike Commit
+// it runs no hooks, so the message is stored as given.
+func (c *Client) CommitIndex(message string) error {
+	head, err := c.GetHead()
+	if err != nil {
+		return err
+	}
+	tree, err := c.WriteIndexTree()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "commit-tree", tree, "-p", head, "-F", "-")
+	cmd.Stdin = strings.NewReader(strings.TrimSpace(message) + "\n")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to commit: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return c.UpdateHead(strings.TrimSpace(string(output)), head, "commit: "+subject)
+}
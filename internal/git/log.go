@@ -0,0 +1,244 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Commit is a commit read from the history
+type Commit struct {
+	Hash    string
+	Subject string
+	Body    string
+	// Parents and the author fields are only set by ShowCommit
+	Parents     []string
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  string
+}
+
+// Message returns the full commit message
+func (c Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Field and record separators for parsing git log output
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// GetCommits returns the non-merge commits in revRange (e.g. "v1.0.0..HEAD"),
+// newest first
+func (c *Client) GetCommits(revRange string) ([]Commit, error) {
+	return c.log(revRange, "--no-merges")
+}
+
+// GetMergeCommits returns the merge commits in revRange, newest first
+func (c *Client) GetMergeCommits(revRange string) ([]Commit, error) {
+	return c.log(revRange, "--merges")
+}
+
+// log reads the commits in revRange selected by the git log filter
+func (c *Client) log(revRange, filter string) ([]Commit, error) {
+	cmd := exec.Command("git", "log", filter, "--format=%H%x1f%s%x1f%b%x1e", revRange, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits in %s: %w", revRange, err)
+	}
+	return parseLog(string(output)), nil
+}
+
+// GetCommitDate returns the committer date of rev as YYYY-MM-DD
+func (c *Client) GetCommitDate(rev string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%cs", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get date of %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseLog splits git log output written with fieldSep and recordSep
+func parseLog(output string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(output, recordSep) {
+		fields := strings.SplitN(strings.TrimSpace(record), fieldSep, 3)
+		if len(fields) < 2 {
+			continue
+		}
+		commit := Commit{Hash: fields[0], Subject: fields[1]}
+		if len(fields) == 3 {
+			commit.Body = strings.TrimSpace(fields[2])
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// IsTag reports whether name is an existing tag
+func (c *Client) IsTag(name string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	return cmd.Run() == nil
+}
+
+// Tag is a tag and the commit it points to
+type Tag struct {
+	Name   string
+	Commit string
+}
+
+// GetTags returns the tags reachable from rev, most recently created first
+func (c *Client) GetTags(rev string) ([]Tag, error) {
+	// *objectname is the tagged commit of annotated tags and empty for lightweight ones
+	cmd := exec.Command("git", "tag", "--merged", rev, "--sort=-creatordate",
+		"--format=%(refname:short)%09%(objectname)%09%(*objectname)")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", rev, err)
+	}
+
+	var tags []Tag
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		tag := Tag{Name: fields[0], Commit: fields[1]}
+		if fields[2] != "" {
+			tag.Commit = fields[2]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// ResolveCommit returns the full hash of the commit rev refers to
+func (c *Client) ResolveCommit(rev string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetCommitPatch returns the changes introduced by the commit rev
+func (c *Client) GetCommitPatch(rev string) (string, error) {
+	cmd := exec.Command("git", "show", "--format=", "--patch", rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get changes of %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CreateTag creates an annotated tag at HEAD
+func (c *Client) CreateTag(name, message string) error {
+	cmd := exec.Command("git", "tag", "--annotate", name, "--file=-")
+	cmd.Stdin = strings.NewReader(message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create tag %s: %s: %w", name, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// commitFormat is the git log format read by parseCommit
+const commitFormat = "%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%s%x1f%b"
+
+// commitFields is the number of fields in commitFormat
+const commitFields = 7
+
+// parseCommit builds a commit from the fields written with commitFormat
+func parseCommit(fields []string) Commit {
+	return Commit{
+		Hash:        strings.TrimSpace(fields[0]),
+		Parents:     strings.Fields(fields[1]),
+		AuthorName:  fields[2],
+		AuthorEmail: fields[3],
+		AuthorDate:  fields[4],
+		Subject:     fields[5],
+		Body:        strings.TrimSpace(fields[6]),
+	}
+}
+
+// ShowCommit returns the commit rev refers to and the changes it introduces
+// with extended context. Merge commits are compared to their first parent.
+func (c *Client) ShowCommit(rev string) (*Commit, string, error) {
+	cmd := exec.Command("git", "show", "--no-color", "-U50", "-m", "--first-parent",
+		"--format="+commitFormat+recordSep, rev, "--")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to show commit %s: %w", rev, err)
+	}
+
+	header, diff, _ := strings.Cut(string(output), recordSep)
+	fields := strings.SplitN(header, fieldSep, commitFields)
+	if len(fields) != commitFields {
+		return nil, "", fmt.Errorf("failed to parse commit %s", rev)
+	}
+	commit := parseCommit(fields)
+	return &commit, strings.TrimSpace(diff), nil
+}
+
+// GetUnpublished returns the hashes of the commits in revRange that are not
+// reachable from any remote-tracking branch or the excluded revisions
+func (c *Client) GetUnpublished(revRange string, exclude ...string) (map[string]bool, error) {
+	args := append([]string{"rev-list", revRange, "--not", "--remotes"}, exclude...)
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unpublished commits in %s: %w", revRange, err)
+	}
+	unpublished := map[string]bool{}
+	for _, hash := range strings.Fields(string(output)) {
+		unpublished[hash] = true
+	}
+	return unpublished, nil
+}
+
+// CommitTree creates a commit with the tree and author of commit, the given
+// parent and message, without touching any branch, and returns its hash
+func (c *Client) CommitTree(commit *Commit, parent, message string) (string, error) {
+	args := []string{"commit-tree", commit.Hash + "^{tree}"}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	cmd := exec.Command("git", append(args, "-F", "-")...)
+	cmd.Stdin = strings.NewReader(message)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+commit.AuthorName,
+		"GIT_AUTHOR_EMAIL="+commit.AuthorEmail,
+		"GIT_AUTHOR_DATE="+commit.AuthorDate,
+	)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to rewrite commit %s: %s: %w", commit.Hash, strings.TrimSpace(stderr.String()), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// UpdateHead moves the current branch from old to new, failing if it no
+// longer points at old
+func (c *Client) UpdateHead(newHead, oldHead, reason string) error {
+	cmd := exec.Command("git", "update-ref", "-m", reason, "HEAD", newHead, oldHead)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update HEAD: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GetMergeHeads returns the commits being merged while a merge is in
+// progress, or nil if there is none
+func (c *Client) GetMergeHeads() ([]string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "MERGE_HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate MERGE_HEAD: %w", err)
+	}
+	content, err := os.ReadFile(strings.TrimSpace(string(output)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MERGE_HEAD: %w", err)
+	}
+	return strings.Fields(string(content)), nil
+}
+
+// GetStagedDiffAgainst returns the differences between rev and the index,
+// limited to paths if any are given
+func (c *Client) GetStagedDiffAgainst(rev string, paths ...string) (string, error) {
+	args := append([]string{"diff", "--cached", "-U10", rev, "--"}, paths...)
+	cmd := exec.Command("git", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get staged changes against %s: %w", rev, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,159 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DiffSource provides the changes that reviews and descriptions are
+// generated from
+type DiffSource interface {
+	// Diff returns the changes as a unified diff
+	Diff() (string, error)
+	// String describes the changes in messages, e.g. "the staged changes"
+	String() string
+}
+
+type diffSource struct {
+	name string
+	diff func() (string, error)
+}
+
+func (s diffSource) Diff() (string, error) { return s.diff() }
+func (s diffSource) String() string        { return s.name }
+
+// Staged returns the changes staged for the next commit
+func Staged(c *Client) DiffSource {
+	return diffSource{"the staged changes", c.GetStagedDiff}
+}
+
+// Worktree returns the staged and unstaged changes to tracked files
+func Worktree(c *Client) DiffSource {
+	return diffSource{"the working tree (untracked files are not considered)", c.GetUnifiedDiff}
+}
+
+// Branch returns the differences between the working tree and base
+func Branch(c *Client, base string) DiffSource {
+	return diffSource{"the current branch compared to " + base, func() (string, error) {
+		return c.GetDiffFromMain(base)
+	}}
+}
+
+// Revision returns the changes introduced by the commit rev
+func Revision(c *Client, rev string) DiffSource {
+	return diffSource{"commit " + rev, func() (string, error) {
+		_, diff, err := c.ShowCommit(rev)
+		return diff, err
+	}}
+}
+
+// Range returns the changes the commits in from..to introduce, compared to
+// their merge base
+func Range(c *Client, from, to string) DiffSource {
+	return diffSource{from + ".." + to, func() (string, error) {
+		return c.GetDiffBetween(from, to)
+	}}
+}
+
+// PatchFile returns the changes in a patch file written by git diff or git
+// format-patch
+func PatchFile(path string) DiffSource {
+	return diffSource{path, func() (string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read patch: %w", err)
+		}
+		return ExtractDiff(string(content))
+	}}
+}
+
+// PatchReader returns the changes in a patch read from r, e.g. stdin
+func PatchReader(name string, r io.Reader) DiffSource {
+	return diffSource{name, func() (string, error) {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return "", fmt.Errorf("failed to read patch from %s: %w", name, err)
+		}
+		return ExtractDiff(string(content))
+	}}
+}
+
+// diffHeaderPrefixes start the lines allowed between "diff --git" and the
+// first hunk
+var diffHeaderPrefixes = []string{
+	"index ", "--- ", "+++ ", "old mode ", "new mode ", "new file mode ", "deleted file mode ",
+	"similarity index ", "dissimilarity index ", "rename from ", "rename to ", "copy from ", "copy to ",
+	"Binary files ",
+}
+
+// ExtractDiff returns the file diffs in a patch, leaving out everything
+// around them, such as the mail headers, commit messages, diffstats and
+// signatures of git format-patch output. Patches with hunks but no diff --git
+// headers, as written by diff -u or svn diff, are an error.
+func ExtractDiff(patch string) (string, error) {
+	var out []string
+	inHeader, oldLeft, newLeft := false, 0, 0
+	// lastContent is the index of the last line that belonged to a hunk
+	lastContent := -1
+	plainHunks := false
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "diff --git ") {
+			out = append(out, line)
+			inHeader, oldLeft, newLeft = true, 0, 0
+			continue
+		}
+		hunk, ok := parseHunkHeader(line)
+		if ok && !inHeader && lastContent < i-2 && i >= 2 &&
+			strings.HasPrefix(lines[i-2], "--- ") && strings.HasPrefix(lines[i-1], "+++ ") {
+			// Outside a git header, --- and +++ lines start a file of a plain
+			// diff, or a hunk quoted in a commit message
+			plainHunks = true
+			continue
+		}
+		if ok && (inHeader || oldLeft+newLeft == 0) && len(out) > 0 {
+			out = append(out, line)
+			inHeader, oldLeft, newLeft = false, hunk.OldLines, hunk.NewLines
+			continue
+		}
+
+		switch {
+		case inHeader && hasAnyPrefix(line, diffHeaderPrefixes):
+			out = append(out, line)
+		case inHeader:
+			// Anything else, e.g. binary patch data, ends the header
+			inHeader = false
+		case oldLeft > 0 || newLeft > 0:
+			switch {
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, "\\"):
+			default:
+				oldLeft--
+				newLeft--
+			}
+			out = append(out, line)
+			lastContent = i
+		case strings.HasPrefix(line, "\\") && len(out) > 0:
+			// "\ No newline at end of file" after the last line of a hunk
+			out = append(out, line)
+		}
+	}
+	if len(out) == 0 && plainHunks {
+		return "", fmt.Errorf("the patch has no diff --git headers. Only patches written by git diff or git format-patch are supported")
+	}
+	return strings.TrimSpace(strings.Join(out, "\n")), nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
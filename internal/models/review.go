@@ -0,0 +1,141 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Review finding severities, from least to most severe
+const (
+	SeverityInfo     = "info"
+	SeverityMinor    = "minor"
+	SeverityMajor    = "major"
+	SeverityCritical = "critical"
+)
+
+// Severities lists the review severities in ascending order
+var Severities = []string{SeverityInfo, SeverityMinor, SeverityMajor, SeverityCritical}
+
+// Review finding categories
+const (
+	CategorySecurity    = "Security"
+	CategoryBug         = "Bug"
+	CategoryPerformance = "Performance"
+	CategoryImprovement = "Improvement"
+)
+
+// Categories lists the fixed set of review categories
+var Categories = []string{CategorySecurity, CategoryBug, CategoryPerformance, CategoryImprovement}
+
+// ParseSeverity validates a severity name
+func ParseSeverity(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if SeverityRank(name) < 0 {
+		return "", fmt.Errorf("unknown severity %q: use %s", name, strings.Join(Severities, ", "))
+	}
+	return name, nil
+}
+
+// SeverityRank returns the position of severity in Severities, or -1 if unknown
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// NormalizeCategory maps a free-text category onto one of Categories
+func NormalizeCategory(category string) string {
+	c := strings.ToLower(category)
+	switch {
+	case containsAny(c, "secur", "vulnerab", "injection", "xss", "auth"):
+		return CategorySecurity
+	case containsAny(c, "bug", "logic", "error", "crash", "correct"):
+		return CategoryBug
+	case containsAny(c, "perf", "optimi", "efficien", "memory"):
+		return CategoryPerformance
+	default:
+		return CategoryImprovement
+	}
+}
+
+// DefaultSeverity is the severity assumed for a category when none is given
+func DefaultSeverity(category string) string {
+	switch NormalizeCategory(category) {
+	case CategorySecurity:
+		return SeverityCritical
+	case CategoryBug:
+		return SeverityMajor
+	case CategoryPerformance:
+		return SeverityMinor
+	default:
+		return SeverityInfo
+	}
+}
+
+// Normalize maps the category onto the fixed set and fills in a missing or
+// unknown severity from the category
+func (r *ReviewComment) Normalize() {
+	r.Category = NormalizeCategory(r.Category)
+	severity, err := ParseSeverity(r.Severity)
+	if err != nil {
+		severity = DefaultSeverity(r.Category)
+	}
+	r.Severity = severity
+}
+
+// Fingerprint identifies a finding independently of its position and wording,
+// so the same issue keeps its identity while surrounding lines move or the
+// model phrases the comment differently between runs
+func (r ReviewComment) Fingerprint() string {
+	anchor := strings.Join(strings.Fields(r.CodeSnippet), " ")
+	if anchor == "" {
+		anchor = fmt.Sprintf("line:%d", r.Line)
+	}
+	category := strings.ToLower(NormalizeCategory(r.Category))
+	sum := sha256.Sum256([]byte(strings.Join([]string{r.File, category, anchor}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprints returns the fingerprint of every finding in review order.
+// Findings that would share one, such as two issues of the same category on
+// the same snippet, get an ordinal mixed in so each stays unique in a report
+func (d *MrReviewDetails) Fingerprints() []string {
+	result := make([]string, len(d.Review))
+	seen := map[string]int{}
+	for i, review := range d.Review {
+		fingerprint := review.Fingerprint()
+		seen[fingerprint]++
+		if n := seen[fingerprint]; n > 1 {
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", fingerprint, n)))
+			fingerprint = hex.EncodeToString(sum[:])
+		}
+		result[i] = fingerprint
+	}
+	return result
+}
+
+// AtLeast returns the findings whose severity is at or above severity
+func (d *MrReviewDetails) AtLeast(severity string) []ReviewComment {
+	threshold := SeverityRank(severity)
+	var result []ReviewComment
+	for _, review := range d.Review {
+		if SeverityRank(review.Severity) >= threshold {
+			result = append(result, review)
+		}
+	}
+	return result
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
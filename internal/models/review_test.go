@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestFingerprintsAreUnique(t *testing.T) {
+	review := &MrReviewDetails{Review: []ReviewComment{
+		{File: "a.go", Line: 10, Category: "Bug", Comment: "nil check", CodeSnippet: "x := y.z"},
+		{File: "a.go", Line: 10, Category: "bug", Comment: "wrong error", CodeSnippet: "x  :=  y.z"},
+		{File: "a.go", Line: 20, Category: "Security", Comment: "no snippet"},
+		{File: "a.go", Line: 20, Category: "Security", Comment: "no snippet either"},
+		{File: "b.go", Line: 20, Category: "Security", Comment: "other file"},
+	}}
+
+	fingerprints := review.Fingerprints()
+	if len(fingerprints) != len(review.Review) {
+		t.Fatalf("Fingerprints() returned %d fingerprints for %d findings", len(fingerprints), len(review.Review))
+	}
+	seen := map[string]bool{}
+	for i, fingerprint := range fingerprints {
+		if seen[fingerprint] {
+			t.Errorf("fingerprint %d (%s) is a duplicate", i, fingerprint)
+		}
+		seen[fingerprint] = true
+	}
+
+	// The first of colliding findings keeps its plain fingerprint, so
+	// reports stay comparable with runs that had no collision
+	if fingerprints[0] != review.Review[0].Fingerprint() {
+		t.Errorf("first fingerprint = %s, want %s", fingerprints[0], review.Review[0].Fingerprint())
+	}
+	if again := review.Fingerprints(); again[1] != fingerprints[1] || again[3] != fingerprints[3] {
+		t.Error("Fingerprints() is not stable between calls")
+	}
+}
+
+func TestFingerprintIgnoresPositionAndWording(t *testing.T) {
+	a := ReviewComment{File: "a.go", Line: 10, Category: "Bug", Comment: "first wording", CodeSnippet: "return err"}
+	b := ReviewComment{File: "a.go", Line: 42, Category: "logic error", Comment: "second wording", CodeSnippet: "return  err\n"}
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("moved and reworded finding changed its fingerprint")
+	}
+
+	c := a
+	c.File = "b.go"
+	if a.Fingerprint() == c.Fingerprint() {
+		t.Error("findings in different files share a fingerprint")
+	}
+}
@@ -2,42 +2,146 @@ package models
 
 // CommitMessage represents a generated commit message
 type CommitMessage struct {
-    Message string `json:"message"`
+	Message string `json:"message" yaml:"message"`
 }
 
 // MrDetails represents MR information
 type MrDetails struct {
-    Title        string        `json:"title"`
-    Description  string        `json:"description"`
-    FileSummaries []FileSummary `json:"fileSummaries"`
+	Title         string        `json:"title" yaml:"title"`
+	Description   string        `json:"description" yaml:"description"`
+	FileSummaries []FileSummary `json:"fileSummaries" yaml:"fileSummaries"`
 }
 
 // FileSummary represents a summary of changes in a file
 type FileSummary struct {
-    File        string `json:"file"`
-    Description string `json:"description"`
+	File        string `json:"file" yaml:"file"`
+	Description string `json:"description" yaml:"description"`
 }
 
 // MrReviewDetails represents PR review feedback
 type MrReviewDetails struct {
-    Review []ReviewComment `json:"review"`
+	Review []ReviewComment `json:"review" yaml:"review"`
 }
 
 // ReviewComment represents a single review comment
 type ReviewComment struct {
-    File        string `json:"file"`
-    Line        int    `json:"line"`
-    Category    string `json:"category"`
-    Comment     string `json:"comment"`
-    CodeSnippet string `json:"codeSnippet"`
+	File        string `json:"file" yaml:"file"`
+	Line        int    `json:"line" yaml:"line"`
+	Category    string `json:"category" yaml:"category"`
+	Severity    string `json:"severity" yaml:"severity"`
+	Comment     string `json:"comment" yaml:"comment"`
+	CodeSnippet string `json:"codeSnippet" yaml:"codeSnippet,omitempty"`
 }
 
 // MrTitle represents a PR title
 type MrTitle struct {
-    Title string `json:"title"`
+	Title string `json:"title" yaml:"title"`
 }
 
 // MrReviewSummary represents a general review summary for a PR
 type MrReviewSummary struct {
-    Summary string `json:"summary"`
-}
\ No newline at end of file
+	Summary string `json:"summary" yaml:"summary"`
+}
+
+// ChangelogNotes are user-facing changelog entries written from commits
+type ChangelogNotes struct {
+	Entries         []ChangelogEntry `json:"entries" yaml:"entries"`
+	BreakingChanges []string         `json:"breakingChanges" yaml:"breakingChanges"`
+}
+
+// ChangelogEntry is the rewritten changelog line for the commit numbered ID
+type ChangelogEntry struct {
+	ID   int    `json:"id" yaml:"id"`
+	Text string `json:"text" yaml:"text"`
+}
+
+// ReleaseNotes are the changes of a release clustered for its release page
+type ReleaseNotes struct {
+	Highlights      []string `json:"highlights" yaml:"highlights"`
+	Features        []string `json:"features" yaml:"features"`
+	Fixes           []string `json:"fixes" yaml:"fixes"`
+	BreakingChanges []string `json:"breakingChanges" yaml:"breakingChanges"`
+}
+
+// BreakingChangeReview is the verdict on whether commits break compatibility
+type BreakingChangeReview struct {
+	Commits []BreakingChangeVerdict `json:"commits" yaml:"commits"`
+}
+
+// BreakingChangeVerdict is the verdict for the commit numbered ID
+type BreakingChangeVerdict struct {
+	ID       int    `json:"id" yaml:"id"`
+	Breaking bool   `json:"breaking" yaml:"breaking"`
+	Reason   string `json:"reason" yaml:"reason"`
+}
+
+// TagMessage represents a generated annotated tag message
+type TagMessage struct {
+	Message string `json:"message" yaml:"message"`
+}
+
+// SplitPlan groups the hunks of a change into separate commits
+type SplitPlan struct {
+	Commits []SplitCommit `json:"commits" yaml:"commits"`
+}
+
+// SplitCommit is a planned commit of the hunks with the given numbers
+type SplitCommit struct {
+	Message string `json:"message" yaml:"message"`
+	Hunks   []int  `json:"hunks" yaml:"hunks"`
+}
+
+// FixupChoices assigns ambiguous hunks to the commits they fix up
+type FixupChoices struct {
+	Hunks []FixupChoice `json:"hunks" yaml:"hunks"`
+}
+
+// FixupChoice names the commit the hunk numbered ID belongs to, or an empty
+// commit if it belongs to none of the candidates
+type FixupChoice struct {
+	ID     int    `json:"id" yaml:"id"`
+	Commit string `json:"commit" yaml:"commit"`
+}
+
+// BranchName holds the parts a branch name is built from
+type BranchName struct {
+	Type   string `json:"type" yaml:"type"`
+	Ticket string `json:"ticket" yaml:"ticket"`
+	Slug   string `json:"slug" yaml:"slug"`
+}
+
+// Explanation describes what a commit or range of commits does
+type Explanation struct {
+	Intent     string               `json:"intent" yaml:"intent"`
+	Components []ExplainedComponent `json:"components" yaml:"components"`
+	Risk       Risk                 `json:"risk" yaml:"risk"`
+	FollowUps  []string             `json:"followUps" yaml:"followUps"`
+	// Walkthrough is only filled in for detailed explanations
+	Walkthrough []string `json:"walkthrough,omitempty" yaml:"walkthrough,omitempty"`
+}
+
+// ExplainedComponent is a part of the codebase and how the changes affect it
+type ExplainedComponent struct {
+	Name   string `json:"name" yaml:"name"`
+	Change string `json:"change" yaml:"change"`
+}
+
+// Risk rates how likely changes are to break something
+type Risk struct {
+	// Level is low, medium or high
+	Level  string `json:"level" yaml:"level"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// LineHistory tells how lines of code came to be
+type LineHistory struct {
+	Narrative string        `json:"narrative" yaml:"narrative"`
+	Rationale string        `json:"rationale" yaml:"rationale"`
+	Timeline  []HistoryStep `json:"timeline" yaml:"timeline"`
+}
+
+// HistoryStep is what one commit did to the lines
+type HistoryStep struct {
+	Commit  string `json:"commit" yaml:"commit"`
+	Summary string `json:"summary" yaml:"summary"`
+}
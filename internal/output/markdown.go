@@ -0,0 +1,126 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// renderMarkdown writes the Markdown form of the gitai result types
+func renderMarkdown(w io.Writer, v any) error {
+	switch v := v.(type) {
+	case *models.CommitMessage:
+		fmt.Fprintf(w, "```\n%s\n```\n", v.Message)
+	case *models.MrTitle:
+		fmt.Fprintf(w, "# %s\n", v.Title)
+	case *models.MrDetails:
+		fmt.Fprintf(w, "# %s\n\n%s", v.Title, MRDescription(v))
+	case *models.MrReviewDetails:
+		fmt.Fprintln(w, "## AI Review")
+		if len(v.Review) == 0 {
+			fmt.Fprintln(w, "\nNo findings.")
+		}
+		for _, review := range v.Review {
+			label := review.Category
+			if review.Severity != "" {
+				label += ", " + review.Severity
+			}
+			fmt.Fprintf(w, "\n### `%s:%d` (%s)\n\n%s\n", review.File, review.Line, label, review.Comment)
+			if review.CodeSnippet != "" {
+				fmt.Fprintf(w, "\n```\n%s\n```\n", review.CodeSnippet)
+			}
+		}
+	case *models.ReleaseNotes:
+		fmt.Fprint(w, ReleaseNotes(v))
+	case *models.Explanation:
+		fmt.Fprint(w, Explanation(v))
+	case *models.LineHistory:
+		fmt.Fprint(w, LineHistory(v))
+	default:
+		fmt.Fprintf(w, "```\n%+v\n```\n", v)
+	}
+	return nil
+}
+
+// MRDescription renders the body of a merge request: the description
+// followed by the per-file summaries
+func MRDescription(details *models.MrDetails) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", details.Description)
+	if len(details.FileSummaries) > 0 {
+		fmt.Fprint(&b, "\n## Changes\n\n")
+		for _, file := range details.FileSummaries {
+			fmt.Fprintf(&b, "- `%s`: %s\n", file.File, file.Description)
+		}
+	}
+	return b.String()
+}
+
+// ReleaseNotes renders release notes as the Markdown body of a release,
+// leaving out empty categories
+func ReleaseNotes(notes *models.ReleaseNotes) string {
+	var b strings.Builder
+	for _, category := range []struct {
+		title   string
+		entries []string
+	}{
+		{"Highlights", notes.Highlights},
+		{"Features", notes.Features},
+		{"Fixes", notes.Fixes},
+		{"Breaking Changes", notes.BreakingChanges},
+	} {
+		if len(category.entries) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			fmt.Fprintln(&b)
+		}
+		fmt.Fprintf(&b, "## %s\n\n", category.title)
+		for _, entry := range category.entries {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+	}
+	return b.String()
+}
+
+// Explanation renders an explanation of changes as Markdown, leaving out
+// empty parts
+func Explanation(explanation *models.Explanation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Intent\n\n%s\n", explanation.Intent)
+	if len(explanation.Components) > 0 {
+		fmt.Fprint(&b, "\n## Affected Components\n\n")
+		for _, component := range explanation.Components {
+			fmt.Fprintf(&b, "- **%s**: %s\n", component.Name, component.Change)
+		}
+	}
+	if len(explanation.Walkthrough) > 0 {
+		fmt.Fprint(&b, "\n## Walkthrough\n\n")
+		for i, step := range explanation.Walkthrough {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+		}
+	}
+	fmt.Fprintf(&b, "\n## Risk: %s\n\n%s\n", explanation.Risk.Level, explanation.Risk.Reason)
+	if len(explanation.FollowUps) > 0 {
+		fmt.Fprint(&b, "\n## Follow-ups\n\n")
+		for _, followUp := range explanation.FollowUps {
+			fmt.Fprintf(&b, "- %s\n", followUp)
+		}
+	}
+	return b.String()
+}
+
+// LineHistory renders the history of lines of code as Markdown
+func LineHistory(history *models.LineHistory) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Why\n\n%s\n\n## History\n\n%s\n", history.Rationale, history.Narrative)
+	if len(history.Timeline) > 0 {
+		fmt.Fprint(&b, "\n## Timeline\n\n")
+		for _, step := range history.Timeline {
+			fmt.Fprintf(&b, "- `%s` %s\n", step.Commit, step.Summary)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format selectable with --output
+type Format string
+
+const (
+	Text     Format = "text"
+	JSON     Format = "json"
+	YAML     Format = "yaml"
+	Markdown Format = "markdown"
+)
+
+// Formats lists every supported format
+var Formats = []Format{Text, JSON, YAML, Markdown}
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == strings.ToLower(name) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q: use text, json, yaml or markdown", name)
+}
+
+// TextRenderer is implemented by results with a human-readable representation
+type TextRenderer interface {
+	RenderText(w io.Writer) error
+}
+
+// MarkdownRenderer is implemented by results with a Markdown representation
+type MarkdownRenderer interface {
+	RenderMarkdown(w io.Writer) error
+}
+
+// Printer writes command results in the selected format
+type Printer struct {
+	w      io.Writer
+	format Format
+}
+
+// NewPrinter creates a printer writing to w
+func NewPrinter(w io.Writer, format Format) *Printer {
+	return &Printer{w: w, format: format}
+}
+
+// Format returns the printer's output format
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// Structured reports whether the output is meant for machines rather than people
+func (p *Printer) Structured() bool {
+	return p.format == JSON || p.format == YAML
+}
+
+// Print writes v in the printer's format. JSON and YAML encode v directly,
+// text and Markdown use the renderers for v.
+func (p *Printer) Print(v any) error {
+	switch p.format {
+	case JSON:
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(v)
+	case YAML:
+		encoder := yaml.NewEncoder(p.w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case Markdown:
+		if r, ok := v.(MarkdownRenderer); ok {
+			return r.RenderMarkdown(p.w)
+		}
+		return renderMarkdown(p.w, v)
+	default:
+		if r, ok := v.(TextRenderer); ok {
+			return r.RenderText(p.w)
+		}
+		return renderText(p.w, v)
+	}
+}
+
+// Notice writes an informational message. Structured formats send it to
+// stderr so stdout stays parseable.
+func (p *Printer) Notice(format string, args ...any) {
+	w := p.w
+	if p.Structured() {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, format+"\n", args...)
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// renderText writes the human-readable form of the gitai result types
+func renderText(w io.Writer, v any) error {
+	switch v := v.(type) {
+	case *models.CommitMessage:
+		fmt.Fprintf(w, "Generated commit message:\n%s\n\n", v.Message)
+	case *models.MrTitle:
+		fmt.Fprintf(w, "Generated MR Title: %s\n", v.Title)
+	case *models.MrDetails:
+		fmt.Fprintf(w, "Generated MR Title: %s\n", v.Title)
+		fmt.Fprintf(w, "Generated MR Description: %s\n", v.Description)
+		fmt.Fprintln(w, "--------------------------------")
+		for _, file := range v.FileSummaries {
+			fmt.Fprintf(w, "File: %s\n", file.File)
+			fmt.Fprintf(w, "Description: %s\n", file.Description)
+			fmt.Fprintln(w, "--------------------------------")
+		}
+	case *models.MrReviewDetails:
+		fmt.Fprintln(w, "AI Review:")
+		for _, review := range v.Review {
+			fmt.Fprintf(w, "\nFile: %s:%d\n", review.File, review.Line)
+			fmt.Fprintf(w, "Category: %s\n", review.Category)
+			if review.Severity != "" {
+				fmt.Fprintf(w, "Severity: %s\n", review.Severity)
+			}
+			fmt.Fprintf(w, "Comment: %s\n", review.Comment)
+			if review.CodeSnippet != "" {
+				fmt.Fprintf(w, "Code Snippet:\n```\n%s\n```\n", review.CodeSnippet)
+			}
+		}
+	case *models.ReleaseNotes:
+		fmt.Fprint(w, ReleaseNotes(v))
+	case *models.Explanation:
+		fmt.Fprint(w, Explanation(v))
+	case *models.LineHistory:
+		fmt.Fprint(w, LineHistory(v))
+	default:
+		fmt.Fprintf(w, "%+v\n", v)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding describes a single redacted value
+type Finding struct {
+	Detector    string
+	Placeholder string
+	Line        int
+}
+
+// Redactor replaces secrets and personal data with stable placeholders
+type Redactor struct {
+	detectors []detector
+	allow     []*regexp.Regexp
+}
+
+type detector struct {
+	name string
+	re   *regexp.Regexp
+	// group is the submatch to redact; 0 redacts the whole match
+	group int
+	// accept filters candidate matches; nil accepts all of them
+	accept func(string) bool
+}
+
+// secretName matches identifiers and keys that name a secret
+const secretName = `[A-Z0-9_]*(?:SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIALS?)[A-Z0-9_]*`
+
+// builtinDetectors are always active unless redaction is disabled entirely
+var builtinDetectors = []detector{
+	{name: "private-key", re: regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)},
+	{name: "aws-access-key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github-token", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{name: "gitlab-token", re: regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}`)},
+	{name: "openai-key", re: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_\-]{20,}`)},
+	{name: "slack-token", re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9\-]{10,}`)},
+	{name: "google-api-key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}`)},
+	{name: "stripe-key", re: regexp.MustCompile(`\b[rs]k_(?:live|test)_[0-9A-Za-z]{24,}`)},
+	{name: "jwt", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)},
+	// A quoted literal assigned to a secret-like name, e.g. password = "..."
+	{
+		name:  "secret-assignment",
+		re:    regexp.MustCompile(`(?i)\b` + secretName + `["']?\s*(?::=|=>|[:=])\s*["']([^\s"'$][^\s"']{5,})["']`),
+		group: 1,
+	},
+	// An unquoted value on an env, .ini or YAML line of its own, e.g.
+	// API_KEY=... Code such as password = getPassword() or
+	// apiKey: cfg.APIKey, is left alone.
+	{
+		name:   "secret-assignment",
+		re:     regexp.MustCompile(`(?im)^[+\- ]?[ \t]*(?:export[ \t]+)?` + secretName + `[ \t]*[:=][ \t]*([^\s"'$(),;{}\[\]]{6,})[ \t]*$`),
+		group:  1,
+		accept: notMemberAccess,
+	},
+	{name: "email", re: regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`)},
+	{name: "high-entropy", re: regexp.MustCompile(`[A-Za-z0-9+/=_\-]{32,}`), accept: highEntropy},
+}
+
+// New creates a redactor with the built-in detectors, the given custom
+// patterns keyed by name, and allow patterns whose matches are never redacted
+func New(patterns map[string]string, allow []string) (*Redactor, error) {
+	r := &Redactor{detectors: append([]detector(nil), builtinDetectors...)}
+
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", name, err)
+		}
+		r.detectors = append(r.detectors, detector{name: name, re: re})
+	}
+
+	for _, pattern := range allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction allow pattern %q: %w", pattern, err)
+		}
+		r.allow = append(r.allow, re)
+	}
+
+	return r, nil
+}
+
+// Redact replaces every detected value in text with a placeholder derived
+// from the value, so the same secret always maps to the same placeholder
+func (r *Redactor) Redact(text string) (string, []Finding) {
+	// Matches are collected on the original text first, so line numbers are
+	// not shifted by earlier replacements. A match overlapping one of an
+	// earlier detector is left to that detector.
+	type match struct {
+		start, end int
+		finding    Finding
+	}
+	var matches []match
+	for _, d := range r.detectors {
+	next:
+		for _, m := range d.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[2*d.group], m[2*d.group+1]
+			if start < 0 {
+				continue
+			}
+			value := text[start:end]
+			if isPlaceholder(value) || r.allowed(value) || (d.accept != nil && !d.accept(value)) {
+				continue
+			}
+			for _, other := range matches {
+				if start < other.end && other.start < end {
+					continue next
+				}
+			}
+
+			matches = append(matches, match{start, end, Finding{
+				Detector:    d.name,
+				Placeholder: Placeholder(d.name, value),
+				Line:        strings.Count(text[:start], "\n") + 1,
+			}})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+	var out strings.Builder
+	var findings []Finding
+	last := 0
+	for _, m := range matches {
+		out.WriteString(text[last:m.start])
+		out.WriteString(m.finding.Placeholder)
+		last = m.end
+		findings = append(findings, m.finding)
+	}
+	out.WriteString(text[last:])
+	return out.String(), findings
+}
+
+// Placeholder returns the stable placeholder for a value found by detector
+func Placeholder(detector, value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return fmt.Sprintf("[REDACTED:%s:%s]", detector, hex.EncodeToString(sum[:4]))
+}
+
+var placeholderPattern = regexp.MustCompile(`^\[?REDACTED:[a-z0-9\-]+:[0-9a-f]{8}\]?$`)
+
+func isPlaceholder(value string) bool {
+	return placeholderPattern.MatchString(value)
+}
+
+func (r *Redactor) allowed(value string) bool {
+	for _, re := range r.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+var memberAccess = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
+// notMemberAccess rejects values that read like code, such as cfg.APIKey
+func notMemberAccess(s string) bool {
+	return !memberAccess.MatchString(s)
+}
+
+// highEntropy reports whether s looks like a random token rather than an
+// identifier or a path
+func highEntropy(s string) bool {
+	var hasDigit, hasUpper, hasLower bool
+	for _, ch := range s {
+		switch {
+		case ch >= '0' && ch <= '9':
+			hasDigit = true
+		case ch >= 'A' && ch <= 'Z':
+			hasUpper = true
+		case ch >= 'a' && ch <= 'z':
+			hasLower = true
+		}
+	}
+	if !hasDigit || !(hasUpper || hasLower) {
+		return false
+	}
+	return entropy(s) >= 4.5
+}
+
+// entropy returns the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, ch := range s {
+		counts[ch]++
+	}
+	var h float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// WriteCheckstyle writes review findings as a Checkstyle XML report, grouped
+// by file in the order the files first appear
+func WriteCheckstyle(w io.Writer, tool Tool, review *models.MrReviewDetails) error {
+	doc := checkstyleReport{Version: "8.0"}
+	fileIndex := map[string]int{}
+	for _, comment := range review.Review {
+		index, ok := fileIndex[comment.File]
+		if !ok {
+			index = len(doc.Files)
+			fileIndex[comment.File] = index
+			doc.Files = append(doc.Files, checkstyleFile{Name: comment.File})
+		}
+		doc.Files[index].Errors = append(doc.Files[index].Errors, checkstyleError{
+			Line:     max(comment.Line, 1),
+			Severity: checkstyleSeverity(comment),
+			Message:  comment.Comment,
+			Source:   tool.Name + "." + ruleID(comment.Category),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// checkstyleSeverity maps the severity of a finding to a Checkstyle severity
+func checkstyleSeverity(comment models.ReviewComment) string {
+	switch severity(comment) {
+	case "critical", "major":
+		return "error"
+	case "minor":
+		return "warning"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// codeQualityIssue is a single entry of a GitLab Code Quality report
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// WriteCodeQuality writes review findings as a GitLab Code Quality report
+func WriteCodeQuality(w io.Writer, tool Tool, review *models.MrReviewDetails) error {
+	issues := make([]codeQualityIssue, 0, len(review.Review))
+	fingerprints := review.Fingerprints()
+	for i, comment := range review.Review {
+		issues = append(issues, codeQualityIssue{
+			Description: comment.Comment,
+			CheckName:   tool.Name + "/" + ruleID(comment.Category),
+			Fingerprint: fingerprints[i],
+			Severity:    severity(comment),
+			Location: codeQualityLocation{
+				Path:  comment.File,
+				Lines: codeQualityLines{Begin: max(comment.Line, 1)},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+// Tool identifies the program that produced a report
+type Tool struct {
+	Name    string
+	Version string
+	URI     string
+}
+
+// Writer renders review findings in a report format
+type Writer func(w io.Writer, tool Tool, review *models.MrReviewDetails) error
+
+var writers = map[string]Writer{
+	"sarif":       WriteSARIF,
+	"codequality": WriteCodeQuality,
+	"checkstyle":  WriteCheckstyle,
+}
+
+// Lookup returns the writer registered for format
+func Lookup(format string) (Writer, error) {
+	writer, ok := writers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown report format %q: use %s", format, strings.Join(Formats(), ", "))
+	}
+	return writer, nil
+}
+
+// Formats lists the registered report formats
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for format := range writers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ruleID derives a stable rule identifier from a category
+func ruleID(category string) string {
+	return strings.ToLower(models.NormalizeCategory(category))
+}
+
+// severity returns the severity of a finding, falling back to the default
+// for its category
+func severity(comment models.ReviewComment) string {
+	if models.SeverityRank(comment.Severity) >= 0 {
+		return comment.Severity
+	}
+	return models.DefaultSeverity(comment.Category)
+}
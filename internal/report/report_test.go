@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var testTool = Tool{Name: "gitai", Version: "1.2.3", URI: "https://github.com/richardamare/gitai"}
+
+var testReview = &models.MrReviewDetails{Review: []models.ReviewComment{
+	{
+		File:        "internal/db/query.go",
+		Line:        42,
+		Category:    "Security",
+		Severity:    "critical",
+		Comment:     "User input is concatenated into the SQL query; use a placeholder.",
+		CodeSnippet: `db.Query("SELECT * FROM users WHERE name = '" + name + "'")`,
+	},
+	{
+		File:        "internal/db/query.go",
+		Line:        42,
+		Category:    "Security",
+		Severity:    "major",
+		Comment:     "The query result is never closed.",
+		CodeSnippet: `db.Query("SELECT * FROM users WHERE name = '" + name + "'")`,
+	},
+	{
+		File:     "cmd/serve.go",
+		Line:     0,
+		Category: "performance issue",
+		Comment:  "The handler allocates a new buffer <per request> & never reuses it.",
+	},
+	{
+		File:     "cmd/serve.go",
+		Line:     7,
+		Category: "Improvement",
+		Severity: "info",
+		Comment:  "Consider naming the timeout constant.",
+	},
+}}
+
+func TestWriters(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			writer, err := Lookup(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := writer(&buf, testTool, testReview); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("%s report differs from %s:\n%s", format, golden, got)
+			}
+		})
+	}
+}
+
+func TestWritersWithoutFindings(t *testing.T) {
+	for _, format := range Formats() {
+		writer, _ := Lookup(format)
+		var buf bytes.Buffer
+		if err := writer(&buf, testTool, &models.MrReviewDetails{}); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+		if buf.Len() == 0 {
+			t.Errorf("%s: empty report", format)
+		}
+	}
+}
+
+func TestLookupUnknownFormat(t *testing.T) {
+	if _, err := Lookup("junit"); err == nil {
+		t.Error("Lookup(junit) succeeded")
+	}
+	if _, err := Lookup("SARIF"); err != nil {
+		t.Errorf("Lookup(SARIF) = %v, want it to ignore case", err)
+	}
+}
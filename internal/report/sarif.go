@@ -0,0 +1,147 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/richardamare/gitai/internal/models"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int           `json:"startLine"`
+	Snippet   *sarifMessage `json:"snippet,omitempty"`
+}
+
+// WriteSARIF writes review findings as a SARIF 2.1.0 log with one rule per category
+func WriteSARIF(w io.Writer, tool Tool, review *models.MrReviewDetails) error {
+	driver := sarifDriver{
+		Name:           tool.Name,
+		Version:        tool.Version,
+		InformationURI: tool.URI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := map[string]int{}
+	results := []sarifResult{}
+
+	fingerprints := review.Fingerprints()
+	for i, comment := range review.Review {
+		id := ruleID(comment.Category)
+		level := sarifLevel(comment)
+		index, ok := ruleIndex[id]
+		if !ok {
+			index = len(driver.Rules)
+			ruleIndex[id] = index
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:                   id,
+				Name:                 comment.Category,
+				ShortDescription:     sarifMessage{Text: comment.Category + " findings from AI review"},
+				DefaultConfiguration: sarifConfiguration{Level: level},
+			})
+		}
+
+		region := sarifRegion{StartLine: max(comment.Line, 1)}
+		if comment.CodeSnippet != "" {
+			region.Snippet = &sarifMessage{Text: comment.CodeSnippet}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    id,
+			RuleIndex: index,
+			Level:     level,
+			Message:   sarifMessage{Text: comment.Comment},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: comment.File, URIBaseID: "%SRCROOT%"},
+					Region:           region,
+				},
+			}},
+			PartialFingerprints: map[string]string{"gitaiFinding/v1": fingerprints[i]},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// sarifLevel maps the severity of a finding to a SARIF level
+func sarifLevel(comment models.ReviewComment) string {
+	switch severity(comment) {
+	case "critical", "major":
+		return "error"
+	case "minor":
+		return "warning"
+	default:
+		return "note"
+	}
+}
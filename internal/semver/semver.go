@@ -0,0 +1,154 @@
+package semver
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/richardamare/gitai/internal/conventional"
+)
+
+// Version is a semantic version as used in release tags
+type Version struct {
+	// Prefix is kept when formatting, e.g. "v" for tags like v1.2.3
+	Prefix     string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Bump is the part of a version a release increments
+type Bump string
+
+const (
+	None  Bump = "none"
+	Patch Bump = "patch"
+	Minor Bump = "minor"
+	Major Bump = "major"
+)
+
+var versionPattern = regexp.MustCompile(`^(v?)(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// Parse parses a tag such as "v1.2.3" or "1.2.3-rc.1"
+func Parse(tag string) (Version, bool) {
+	match := versionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return Version{}, false
+	}
+	major, _ := strconv.Atoi(match[2])
+	minor, _ := strconv.Atoi(match[3])
+	patch, _ := strconv.Atoi(match[4])
+	return Version{Prefix: match[1], Major: major, Minor: minor, Patch: patch, Prerelease: match[5]}, true
+}
+
+// String formats the version with its prefix
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Less reports whether v has a lower precedence than other, following the
+// semver rules for prereleases: 1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-beta <
+// 1.0.0-beta.2 < 1.0.0-beta.11 < 1.0.0-rc.1 < 1.0.0
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	if v.Patch != other.Patch {
+		return v.Patch < other.Patch
+	}
+	if v.Prerelease == "" || other.Prerelease == "" {
+		return v.Prerelease != "" && other.Prerelease == ""
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease) < 0
+}
+
+// comparePrerelease compares two prerelease strings identifier by identifier.
+// Numeric identifiers compare numerically and sort before alphanumeric ones;
+// a shorter list of otherwise equal identifiers sorts first.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return cmp.Compare(an, bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// Next returns the version after v for the given bump. A prerelease is
+// released as its version unless the bump goes beyond it.
+func (v Version) Next(bump Bump) Version {
+	if bump == None {
+		return v
+	}
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	if v.Prerelease != "" {
+		// 1.2.0-rc.1 is followed by 1.2.0 for minor and patch changes
+		if bump == Major && (v.Minor != 0 || v.Patch != 0) {
+			return Version{Prefix: v.Prefix, Major: v.Major + 1}
+		}
+		if bump == Minor && v.Patch != 0 {
+			return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor + 1}
+		}
+		return next
+	}
+
+	switch bump {
+	case Major:
+		return Version{Prefix: v.Prefix, Major: v.Major + 1}
+	case Minor:
+		return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor + 1}
+	case Patch:
+		next.Patch++
+	}
+	return next
+}
+
+// BumpFor returns the bump the commits call for: major for breaking
+// changes, minor for features and patch for anything else. Before 1.0.0
+// breaking changes only bump the minor version.
+func BumpFor(current Version, commits []conventional.Commit) Bump {
+	bump := None
+	for _, commit := range commits {
+		switch {
+		case commit.Breaking:
+			return breakingBump(current)
+		case commit.Type == "feat":
+			bump = Minor
+		case bump == None:
+			bump = Patch
+		}
+	}
+	return bump
+}
+
+// breakingBump returns the bump for a breaking change of current
+func breakingBump(current Version) Bump {
+	if current.Major == 0 {
+		return Minor
+	}
+	return Major
+}
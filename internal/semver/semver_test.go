@@ -0,0 +1,156 @@
+package semver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/richardamare/gitai/internal/conventional"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Version
+		ok   bool
+	}{
+		{"v1.2.3", Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3}, true},
+		{"0.10.0", Version{Minor: 10}, true},
+		{"1.0.0-rc.1", Version{Major: 1, Prerelease: "rc.1"}, true},
+		{"v2.0.0-beta.2+build.5", Version{Prefix: "v", Major: 2, Prerelease: "beta.2"}, true},
+		{"1.2", Version{}, false},
+		{"v01.2.3", Version{}, false},
+		{"release-1.2.3", Version{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.tag)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, tag := range []string{"v1.2.3", "0.1.0", "1.0.0-rc.1"} {
+		v, _ := Parse(tag)
+		if got := v.String(); got != tag {
+			t.Errorf("Parse(%q).String() = %q", tag, got)
+		}
+	}
+}
+
+func TestLessOrdersPrereleases(t *testing.T) {
+	// In ascending precedence, from the examples of the semver spec
+	ordered := []string{
+		"0.9.9",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0-rc.2",
+		"1.0.0-rc.10",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	versions := make([]Version, len(ordered))
+	for i, tag := range ordered {
+		v, ok := Parse(tag)
+		if !ok {
+			t.Fatalf("Parse(%q) failed", tag)
+		}
+		versions[i] = v
+	}
+
+	for i := 0; i < len(versions)-1; i++ {
+		a, b := versions[i], versions[i+1]
+		if !a.Less(b) || b.Less(a) {
+			t.Errorf("want %s < %s", a, b)
+		}
+	}
+	if versions[0].Less(versions[0]) {
+		t.Errorf("%s < itself", versions[0])
+	}
+
+	shuffled := slices.Clone(versions)
+	slices.Reverse(shuffled)
+	slices.SortFunc(shuffled, func(a, b Version) int {
+		switch {
+		case a.Less(b):
+			return -1
+		case b.Less(a):
+			return 1
+		}
+		return 0
+	})
+	if !slices.Equal(shuffled, versions) {
+		t.Errorf("sorted versions = %v, want %v", shuffled, versions)
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		current string
+		bump    Bump
+		want    string
+	}{
+		{"v1.2.3", None, "v1.2.3"},
+		{"v1.2.3", Patch, "v1.2.4"},
+		{"v1.2.3", Minor, "v1.3.0"},
+		{"v1.2.3", Major, "v2.0.0"},
+		{"0.3.1", Minor, "0.4.0"},
+		{"0.3.1", Patch, "0.3.2"},
+		// A prerelease is released as its version unless the bump goes beyond it
+		{"1.2.0-rc.1", Patch, "1.2.0"},
+		{"1.2.0-rc.1", Minor, "1.2.0"},
+		{"1.2.0-rc.1", Major, "2.0.0"},
+		{"1.2.3-rc.1", Minor, "1.3.0"},
+		{"2.0.0-beta.2", Major, "2.0.0"},
+	}
+	for _, tt := range tests {
+		v, _ := Parse(tt.current)
+		if got := v.Next(tt.bump).String(); got != tt.want {
+			t.Errorf("Parse(%q).Next(%s) = %s, want %s", tt.current, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func TestBumpFor(t *testing.T) {
+	parse := func(messages ...string) []conventional.Commit {
+		var commits []conventional.Commit
+		for _, message := range messages {
+			commits = append(commits, conventional.Parse(message))
+		}
+		return commits
+	}
+
+	tests := []struct {
+		name    string
+		current string
+		commits []conventional.Commit
+		want    Bump
+	}{
+		{"no commits", "1.0.0", nil, None},
+		{"fix", "1.0.0", parse("fix: handle nil"), Patch},
+		{"chore", "1.0.0", parse("chore: update deps"), Patch},
+		{"non-conventional", "1.0.0", parse("Update README"), Patch},
+		{"feat", "1.0.0", parse("fix: a", "feat(api): b", "docs: c"), Minor},
+		{"bang", "1.4.2", parse("fix: a", "feat!: drop v1 API"), Major},
+		{"footer", "1.4.2", parse("refactor: a\n\nBREAKING CHANGE: config keys renamed"), Major},
+		{"pre-1.0 breaking", "0.4.2", parse("feat!: drop v1 API"), Minor},
+		{"pre-1.0 feat", "0.4.2", parse("feat: b"), Minor},
+		{"pre-1.0 fix", "0.4.2", parse("fix: a"), Patch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, _ := Parse(tt.current)
+			if got := BumpFor(current, tt.commits); got != tt.want {
+				t.Errorf("BumpFor(%s) = %s, want %s", tt.current, got, tt.want)
+			}
+		})
+	}
+}
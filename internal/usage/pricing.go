@@ -0,0 +1,41 @@
+package usage
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	Input  float64 `mapstructure:"input" json:"input"`
+	Output float64 `mapstructure:"output" json:"output"`
+}
+
+// Prices maps model names to their price
+type Prices map[string]Price
+
+// DefaultPrices returns list prices for the models gitai commonly uses
+func DefaultPrices() Prices {
+	return Prices{
+		"gpt-4o-mini":  {Input: 0.15, Output: 0.60},
+		"gpt-4o":       {Input: 2.50, Output: 10.00},
+		"gpt-4.1-mini": {Input: 0.40, Output: 1.60},
+		"gpt-4.1":      {Input: 2.00, Output: 8.00},
+	}
+}
+
+// Merge returns a copy of p with the entries of overrides applied on top
+func (p Prices) Merge(overrides Prices) Prices {
+	merged := Prices{}
+	for name, price := range p {
+		merged[name] = price
+	}
+	for name, price := range overrides {
+		merged[name] = price
+	}
+	return merged
+}
+
+// Cost returns the USD cost of a request. Unknown models cost nothing.
+func (p Prices) Cost(model string, promptTokens, completionTokens int) float64 {
+	price, ok := p[model]
+	if !ok {
+		return 0
+	}
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1_000_000
+}
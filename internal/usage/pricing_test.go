@@ -0,0 +1,56 @@
+package usage
+
+import "testing"
+
+func TestPricesMerge(t *testing.T) {
+	defaults := DefaultPrices()
+	merged := defaults.Merge(Prices{
+		"gpt-4o":      {Input: 1, Output: 2},
+		"local-llm":   {Input: 0, Output: 0},
+		"my-finetune": {Input: 3, Output: 12},
+	})
+
+	tests := []struct {
+		name  string
+		model string
+		want  Price
+	}{
+		{name: "default kept", model: "gpt-4o-mini", want: Price{Input: 0.15, Output: 0.60}},
+		{name: "default overridden", model: "gpt-4o", want: Price{Input: 1, Output: 2}},
+		{name: "model added", model: "my-finetune", want: Price{Input: 3, Output: 12}},
+		{name: "free model added", model: "local-llm", want: Price{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := merged[tt.model]; !ok || got != tt.want {
+				t.Errorf("merged[%s] = %+v, %v, want %+v", tt.model, got, ok, tt.want)
+			}
+		})
+	}
+
+	// Merge does not modify the receiver
+	if defaults["gpt-4o"] != (Price{Input: 2.50, Output: 10.00}) {
+		t.Errorf("Merge() changed the defaults: %+v", defaults["gpt-4o"])
+	}
+}
+
+func TestPricesCost(t *testing.T) {
+	prices := Prices{"model": {Input: 2, Output: 8}}
+	tests := []struct {
+		name               string
+		model              string
+		prompt, completion int
+		want               float64
+	}{
+		{name: "one million each", model: "model", prompt: 1_000_000, completion: 1_000_000, want: 10},
+		{name: "small request", model: "model", prompt: 1000, completion: 500, want: 0.006},
+		{name: "unknown model", model: "other", prompt: 1000, completion: 500, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prices.Cost(tt.model, tt.prompt, tt.completion); got != tt.want {
+				t.Errorf("Cost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
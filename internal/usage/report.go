@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Group is the aggregated usage of all records sharing a key
+type Group struct {
+	Key              string  `json:"key" yaml:"key"`
+	Requests         int     `json:"requests" yaml:"requests"`
+	PromptTokens     int     `json:"promptTokens" yaml:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens" yaml:"completionTokens"`
+	Cost             float64 `json:"cost" yaml:"cost"`
+}
+
+// Dimensions maps the supported grouping names to the record field they use
+var Dimensions = map[string]func(Record) string{
+	"day":     func(r Record) string { return r.Time.Local().Format(time.DateOnly) },
+	"command": func(r Record) string { return r.Command },
+	"repo":    func(r Record) string { return r.Repo },
+	"model":   func(r Record) string { return r.Model },
+}
+
+// Summarize aggregates records by the given dimensions, sorted by key
+func Summarize(records []Record, dimensions []string) []Group {
+	groups := map[string]*Group{}
+	for _, record := range records {
+		parts := make([]string, 0, len(dimensions))
+		for _, dimension := range dimensions {
+			parts = append(parts, Dimensions[dimension](record))
+		}
+		key := strings.Join(parts, " / ")
+
+		group, ok := groups[key]
+		if !ok {
+			group = &Group{Key: key}
+			groups[key] = group
+		}
+		group.Requests++
+		group.PromptTokens += record.PromptTokens
+		group.CompletionTokens += record.CompletionTokens
+		group.Cost += record.Cost
+	}
+
+	result := make([]Group, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Since returns the records made at or after t
+func Since(records []Record, t time.Time) []Record {
+	var result []Record
+	for _, record := range records {
+		if !record.Time.Before(t) {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+// MonthToDate returns the total cost of records in the calendar month of now
+func MonthToDate(records []Record, now time.Time) float64 {
+	var total float64
+	for _, record := range Since(records, monthStart(now)) {
+		total += record.Cost
+	}
+	return total
+}
+
+// monthStart returns the start of the calendar month of t
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,96 @@
+package usage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	day := time.Date(2026, 3, 14, 12, 0, 0, 0, time.Local)
+	records := []Record{
+		{Time: day, Command: "commit", Repo: "a", Model: "gpt-4o-mini", PromptTokens: 100, CompletionTokens: 10, Cost: 0.5},
+		{Time: day, Command: "commit", Repo: "b", Model: "gpt-4o", PromptTokens: 200, CompletionTokens: 20, Cost: 1},
+		{Time: day.AddDate(0, 0, 1), Command: "mr review", Repo: "a", Model: "gpt-4o", PromptTokens: 300, CompletionTokens: 30, Cost: 2},
+	}
+
+	tests := []struct {
+		name       string
+		dimensions []string
+		want       []Group
+	}{
+		{
+			name:       "command",
+			dimensions: []string{"command"},
+			want: []Group{
+				{Key: "commit", Requests: 2, PromptTokens: 300, CompletionTokens: 30, Cost: 1.5},
+				{Key: "mr review", Requests: 1, PromptTokens: 300, CompletionTokens: 30, Cost: 2},
+			},
+		},
+		{
+			name:       "day and model",
+			dimensions: []string{"day", "model"},
+			want: []Group{
+				{Key: "2026-03-14 / gpt-4o", Requests: 1, PromptTokens: 200, CompletionTokens: 20, Cost: 1},
+				{Key: "2026-03-14 / gpt-4o-mini", Requests: 1, PromptTokens: 100, CompletionTokens: 10, Cost: 0.5},
+				{Key: "2026-03-15 / gpt-4o", Requests: 1, PromptTokens: 300, CompletionTokens: 30, Cost: 2},
+			},
+		},
+		{
+			name:       "no dimensions",
+			dimensions: nil,
+			want:       []Group{{Key: "", Requests: 3, PromptTokens: 600, CompletionTokens: 60, Cost: 3.5}},
+		},
+		{
+			name:       "repo",
+			dimensions: []string{"repo"},
+			want: []Group{
+				{Key: "a", Requests: 2, PromptTokens: 400, CompletionTokens: 40, Cost: 2.5},
+				{Key: "b", Requests: 1, PromptTokens: 200, CompletionTokens: 20, Cost: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(records, tt.dimensions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Summarize(%v) =\n%+v\nwant\n%+v", tt.dimensions, got, tt.want)
+			}
+		})
+	}
+
+	if got := Summarize(nil, []string{"day"}); len(got) != 0 {
+		t.Errorf("Summarize(nil) = %+v, want no groups", got)
+	}
+}
+
+func TestMonthToDate(t *testing.T) {
+	utc := time.UTC
+	records := []Record{
+		{Time: time.Date(2026, 2, 28, 23, 59, 59, 0, utc), Cost: 1},
+		{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, utc), Cost: 2},
+		{Time: time.Date(2026, 3, 31, 23, 59, 59, 0, utc), Cost: 4},
+		{Time: time.Date(2025, 3, 15, 0, 0, 0, 0, utc), Cost: 8},
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want float64
+	}{
+		{name: "first instant of the month", now: time.Date(2026, 3, 1, 0, 0, 0, 0, utc), want: 6},
+		{name: "end of the month", now: time.Date(2026, 3, 31, 23, 59, 59, 0, utc), want: 6},
+		{name: "last instant of the previous month", now: time.Date(2026, 2, 28, 23, 59, 59, 0, utc), want: 7},
+		{name: "next month", now: time.Date(2026, 4, 1, 0, 0, 0, 0, utc), want: 0},
+		// The month starts at midnight in the location of now
+		{name: "other time zone", now: time.Date(2026, 3, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600)), want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MonthToDate(records, tt.now); got != tt.want {
+				t.Errorf("MonthToDate(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tracker records the usage of a single gitai invocation and enforces the
+// monthly budget
+type Tracker struct {
+	ledger  *Ledger
+	prices  Prices
+	budget  float64
+	command string
+	repo    string
+	// spent is the cost of the month starting at spentSince, read from the
+	// ledger once and kept up to date by Record
+	spent      float64
+	spentSince time.Time
+
+	Requests         int
+	CacheHits        int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64
+}
+
+// NewTracker creates a tracker that appends to ledger. A budget of zero
+// disables budget enforcement.
+func NewTracker(ledger *Ledger, prices Prices, budget float64, command, repo string) *Tracker {
+	return &Tracker{
+		ledger:  ledger,
+		prices:  prices,
+		budget:  budget,
+		command: command,
+		repo:    repo,
+	}
+}
+
+// Allow returns an error if the monthly budget has already been spent. The
+// ledger is only read again when a new month starts.
+func (t *Tracker) Allow() error {
+	if t.budget <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if start := monthStart(now); !t.spentSince.Equal(start) {
+		records, err := t.ledger.Records()
+		if err != nil {
+			return err
+		}
+		t.spent, t.spentSince = MonthToDate(records, now), start
+	}
+
+	if t.spent >= t.budget {
+		return fmt.Errorf("monthly AI budget of $%.2f exceeded ($%.2f spent this month)", t.budget, t.spent)
+	}
+	return nil
+}
+
+// Record accounts for a completed request and appends it to the ledger
+func (t *Tracker) Record(model string, promptTokens, completionTokens int) error {
+	cost := t.prices.Cost(model, promptTokens, completionTokens)
+
+	t.Requests++
+	t.PromptTokens += promptTokens
+	t.CompletionTokens += completionTokens
+	t.Cost += cost
+	t.spent += cost
+
+	return t.ledger.Append(Record{
+		Time:             time.Now().UTC(),
+		Command:          t.command,
+		Repo:             t.repo,
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		Cost:             cost,
+	})
+}
+
+// RecordCacheHit accounts for a request served from the response cache
+func (t *Tracker) RecordCacheHit() {
+	t.CacheHits++
+}
+
+// Summary returns a one-line description of the tracked usage
+func (t *Tracker) Summary() string {
+	return fmt.Sprintf("%d request(s), %d cached, %d prompt + %d completion tokens, $%.4f",
+		t.Requests, t.CacheHits, t.PromptTokens, t.CompletionTokens, t.Cost)
+}
@@ -0,0 +1,69 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackerAllow(t *testing.T) {
+	now := time.Now()
+	lastMonth := monthStart(now).Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		budget  float64
+		records []Record
+		allowed bool
+	}{
+		{name: "no budget", budget: 0, records: []Record{{Time: now, Cost: 100}}, allowed: true},
+		{name: "under budget", budget: 5, records: []Record{{Time: now, Cost: 2}, {Time: now, Cost: 2.5}}, allowed: true},
+		{name: "budget reached", budget: 5, records: []Record{{Time: now, Cost: 2.5}, {Time: now, Cost: 2.5}}, allowed: false},
+		{name: "spent last month", budget: 5, records: []Record{{Time: lastMonth, Cost: 50}, {Time: now, Cost: 1}}, allowed: true},
+		{name: "empty ledger", budget: 5, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := NewLedger(filepath.Join(t.TempDir(), "usage.jsonl"))
+			for _, record := range tt.records {
+				if err := ledger.Append(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := NewTracker(ledger, DefaultPrices(), tt.budget, "commit", "repo").Allow()
+			if tt.allowed && err != nil {
+				t.Errorf("Allow() = %v, want allowed", err)
+			}
+			if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "budget of $5.00 exceeded")) {
+				t.Errorf("Allow() = %v, want a budget error", err)
+			}
+		})
+	}
+}
+
+func TestTrackerAllowCountsRecordedRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	ledger := NewLedger(path)
+	tracker := NewTracker(ledger, Prices{"model": {Input: 1_000_000, Output: 0}}, 5, "commit", "repo")
+
+	if err := tracker.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := tracker.Record("model", 1, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Allow must not read the ledger again: the recorded requests count
+	// although the ledger now holds nothing
+	if err := os.WriteFile(path, []byte("not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Allow(); err == nil {
+		t.Error("Allow() after spending the budget succeeded")
+	}
+}